@@ -0,0 +1,290 @@
+package ginney
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type capturedBody struct {
+	contentType string
+	data        []byte
+	size        int64
+	truncated   bool
+	// notCaptured is set when the max size is 0, the body is neither read nor logged
+	notCaptured bool
+}
+
+type multiReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// captureRequestBody reads up to maxSize bytes of the body and puts them back in front of the rest,
+// so the handler still reads the whole body.
+func captureRequestBody(req *http.Request, maxSize int64) capturedBody {
+	captured := capturedBody{
+		contentType: req.Header.Get(ContentTypeHeaderKey),
+		size:        req.ContentLength,
+	}
+	if req.Body == nil || req.Body == http.NoBody || !isLoggableBody(captured.contentType) {
+		return captured
+	}
+	if maxSize <= 0 {
+		captured.notCaptured = true
+		return captured
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(req.Body, maxSize+1))
+	req.Body = &multiReadCloser{
+		Reader: io.MultiReader(bytes.NewReader(data), req.Body),
+		Closer: req.Body,
+	}
+	if err != nil {
+		return captured
+	}
+
+	captured.truncated = int64(len(data)) > maxSize
+	if captured.truncated {
+		data = data[:maxSize]
+	} else {
+		captured.size = int64(len(data))
+	}
+	captured.data = data
+
+	return captured
+}
+
+func captureOutboundRequestBody(req *http.Request, maxSize int64) capturedBody {
+	captured := capturedBody{
+		contentType: req.Header.Get(ContentTypeHeaderKey),
+		size:        req.ContentLength,
+	}
+	if req.Body == nil || req.GetBody == nil || !isLoggableBody(captured.contentType) {
+		return captured
+	}
+	if maxSize <= 0 {
+		captured.notCaptured = true
+		return captured
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return captured
+	}
+	defer body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return captured
+	}
+
+	captured.truncated = int64(len(data)) > maxSize
+	if captured.truncated {
+		data = data[:maxSize]
+	} else {
+		captured.size = int64(len(data))
+	}
+	captured.data = data
+
+	return captured
+}
+
+func (b capturedBody) String() string {
+	mediaType, params, _ := mime.ParseMediaType(b.contentType)
+
+	switch {
+	case !isLoggableBody(b.contentType) && b.size < 0:
+		return fmt.Sprintf("[%s body]", mediaType)
+	case !isLoggableBody(b.contentType):
+		return fmt.Sprintf("[%s body: %d bytes]", mediaType, b.size)
+	case b.notCaptured && b.size < 0:
+		return "[body not captured]"
+	case b.notCaptured:
+		return fmt.Sprintf("[body not captured: %d bytes]", b.size)
+	case len(b.data) == 0 && b.size > 0:
+		return fmt.Sprintf("[unreadable body: %d bytes]", b.size)
+	case len(b.data) == 0:
+		return "{}"
+	case mediaType == "application/x-www-form-urlencoded":
+		return formBodyToString(b.data)
+	case mediaType == "multipart/form-data":
+		return multipartBodyToString(b.data, params["boundary"])
+	case b.truncated && b.size < 0:
+		// chunked bodies have no content length, only the part read is known
+		return fmt.Sprintf("[truncated body: >%d bytes]", len(b.data))
+	case b.truncated:
+		return fmt.Sprintf("[truncated body: %d bytes]", b.size)
+	default:
+		var bodyData interface{}
+		if err := json.Unmarshal(b.data, &bodyData); err != nil {
+			return fmt.Sprintf("[unparsable body: %d bytes]", b.size)
+		}
+		return jsonBodyToString(bodyData)
+	}
+}
+
+func isLoggableBody(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" ||
+		strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/x-www-form-urlencoded" ||
+		mediaType == "multipart/form-data"
+}
+
+func formBodyToString(data []byte) string {
+	values, _ := url.ParseQuery(string(data))
+	return jsonBodyToString(formValuesToJson(values))
+}
+
+func formValuesToJson(values map[string][]string) map[string]interface{} {
+	bodyData := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if len(value) == 1 {
+			bodyData[key] = value[0]
+			continue
+		}
+
+		items := make([]interface{}, len(value))
+		for index, item := range value {
+			items[index] = item
+		}
+		bodyData[key] = items
+	}
+	return bodyData
+}
+
+type multipartFileSummary struct {
+	Field    string `json:"field"`
+	Filename string `json:"filename"`
+	Size     int    `json:"size"`
+}
+
+func multipartBodyToString(data []byte, boundary string) string {
+	fields := map[string][]string{}
+	files := []multipartFileSummary{}
+
+	// a truncated body ends with a broken part, so it is summarized up to the last complete part
+	reader := multipart.NewReader(bytes.NewReader(data), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+
+		content, err := ioutil.ReadAll(part)
+		if err != nil {
+			break
+		}
+
+		if part.FileName() != "" {
+			files = append(files, multipartFileSummary{
+				Field:    part.FormName(),
+				Filename: part.FileName(),
+				Size:     len(content),
+			})
+			continue
+		}
+		fields[part.FormName()] = append(fields[part.FormName()], string(content))
+	}
+
+	jsonBytes, _ := json.Marshal(struct {
+		Fields interface{}            `json:"fields"`
+		Files  []multipartFileSummary `json:"files"`
+	}{
+		Fields: censorJsonValue(formValuesToJson(fields), nil, 0),
+		Files:  files,
+	})
+	return string(jsonBytes)
+}
+
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body    *bytes.Buffer
+	maxSize int64
+}
+
+func newBodyCaptureWriter(writer gin.ResponseWriter, maxSize int64) *bodyCaptureWriter {
+	return &bodyCaptureWriter{ResponseWriter: writer, body: new(bytes.Buffer), maxSize: maxSize}
+}
+
+func (w *bodyCaptureWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyCaptureWriter) WriteString(data string) (int, error) {
+	w.capture([]byte(data))
+	return w.ResponseWriter.WriteString(data)
+}
+
+func (w *bodyCaptureWriter) capture(data []byte) {
+	// keeping one extra byte to know the body was truncated
+	if remaining := w.maxSize + 1 - int64(w.body.Len()); remaining > 0 {
+		if int64(len(data)) > remaining {
+			data = data[:remaining]
+		}
+		w.body.Write(data)
+	}
+}
+
+func (w *bodyCaptureWriter) captured() capturedBody {
+	data := w.body.Bytes()
+	captured := capturedBody{
+		contentType: w.Header().Get(ContentTypeHeaderKey),
+		size:        int64(w.Size()),
+		truncated:   int64(len(data)) > w.maxSize,
+	}
+	if captured.truncated {
+		data = data[:w.maxSize]
+	}
+	captured.data = data
+	return captured
+}
+
+func grpcMessageToCapturedBody(message interface{}, maxSize int64) capturedBody {
+	captured := capturedBody{contentType: "application/json"}
+
+	data, err := json.Marshal(message)
+	if err != nil || string(data) == "null" {
+		return captured
+	}
+
+	captured.size = int64(len(data))
+	captured.truncated = captured.size > maxSize
+	if captured.truncated {
+		data = data[:maxSize]
+	}
+	captured.data = data
+	return captured
+}
+
+func selectHeaders(headerNames []string, get func(name string) string) map[string]string {
+	if len(headerNames) == 0 {
+		return nil
+	}
+
+	headers := make(map[string]string, len(headerNames))
+	for _, name := range headerNames {
+		if value := get(name); value != "" {
+			headers[name] = value
+		}
+	}
+	return headers
+}
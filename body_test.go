@@ -0,0 +1,112 @@
+package ginney
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCaptureRequestBody(t *testing.T) {
+	t.Run("Happy - body is restored for the handler", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/random", strings.NewReader(`{"example":"hello"}`))
+
+		captured := captureRequestBody(req, DefaultMaxBodyLogSize)
+
+		restoredBody, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"example":"hello"}`, string(restoredBody))
+		assert.Equal(t, `{"example":"hello"}`, captured.String())
+	})
+
+	t.Run("Happy - body larger than max size is truncated but fully restored", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/random", strings.NewReader(`{"example":"hello"}`))
+		req.Header.Set(ContentTypeHeaderKey, "application/json")
+
+		captured := captureRequestBody(req, 5)
+
+		restoredBody, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"example":"hello"}`, string(restoredBody))
+		assert.Equal(t, "[truncated body: 19 bytes]", captured.String())
+	})
+
+	t.Run("Happy - chunked body larger than max size reports the size read", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/random", strings.NewReader(`{"example":"hello"}`))
+		req.Header.Set(ContentTypeHeaderKey, "application/json")
+		req.ContentLength = -1
+
+		captured := captureRequestBody(req, 5)
+
+		restoredBody, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"example":"hello"}`, string(restoredBody))
+		assert.Equal(t, "[truncated body: >5 bytes]", captured.String())
+	})
+
+	t.Run("Happy - body is not captured with max size 0", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/random", strings.NewReader(`{"example":"hello"}`))
+		req.Header.Set(ContentTypeHeaderKey, "application/json")
+
+		assert.Equal(t, "[body not captured: 19 bytes]", captureRequestBody(req, 0).String())
+
+		req.ContentLength = -1
+		captured := captureRequestBody(req, 0)
+
+		restoredBody, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"example":"hello"}`, string(restoredBody))
+		assert.Equal(t, "[body not captured]", captured.String())
+	})
+
+	t.Run("Happy - form urlencoded body is censored", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/random", strings.NewReader("username=john&password=secret&tag=a&tag=b"))
+		req.Header.Set(ContentTypeHeaderKey, "application/x-www-form-urlencoded")
+
+		captured := captureRequestBody(req, DefaultMaxBodyLogSize)
+
+		assert.Equal(t, `{"password":"[HIDDEN_FIELD]","tag":["a","b"],"username":"john"}`, captured.String())
+	})
+
+	t.Run("Happy - multipart body is summarized", func(t *testing.T) {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		_ = writer.WriteField("name", "john")
+		_ = writer.WriteField("password", "secret")
+		fileWriter, _ := writer.CreateFormFile("avatar", "avatar.png")
+		_, _ = fileWriter.Write([]byte("fake-png-content"))
+		_ = writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/random", body)
+		req.Header.Set(ContentTypeHeaderKey, writer.FormDataContentType())
+
+		captured := captureRequestBody(req, DefaultMaxBodyLogSize)
+
+		assert.Equal(t,
+			`{"fields":{"name":"john","password":"[HIDDEN_FIELD]"},"files":[{"field":"avatar","filename":"avatar.png","size":16}]}`,
+			captured.String(),
+		)
+	})
+
+	t.Run("Happy - binary body is skipped without reading it", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/random", strings.NewReader("binary-content"))
+		req.Header.Set(ContentTypeHeaderKey, "application/octet-stream")
+
+		captured := captureRequestBody(req, DefaultMaxBodyLogSize)
+
+		assert.Nil(t, captured.data)
+		assert.Equal(t, "[application/octet-stream body: 14 bytes]", captured.String())
+	})
+
+	t.Run("Happy - invalid json body is not logged in clear text", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/random", strings.NewReader("password=secret"))
+
+		captured := captureRequestBody(req, DefaultMaxBodyLogSize)
+
+		assert.Equal(t, "[unparsable body: 15 bytes]", captured.String())
+	})
+}
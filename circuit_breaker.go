@@ -0,0 +1,283 @@
+package ginney
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const ErrorCodeCircuitOpen = "CIRCUIT_OPEN"
+
+// ErrCircuitOpen is returned without calling the downstream service, it is rendered as 503 and codes.Unavailable
+var ErrCircuitOpen = NewError(http.StatusServiceUnavailable, "circuit breaker is open").WithCode(ErrorCodeCircuitOpen)
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type CircuitBreakerPolicy struct {
+	// trips after this many failures in a row, zero disables the trigger
+	ConsecutiveFailures int
+	// trips when the failure ratio within Window reaches this value once MinRequests are seen, zero disables the trigger
+	FailureRatio float64
+	MinRequests  int
+	Window       time.Duration
+	// how long the circuit stays open before probe requests are let through
+	Cooldown time.Duration
+	// probe requests allowed at once in half-open, the same number of successes closes the circuit
+	HalfOpenRequests int
+	OnStateChange    func(name string, from CircuitState, to CircuitState)
+}
+
+func DefaultCircuitBreakerPolicy() CircuitBreakerPolicy {
+	return CircuitBreakerPolicy{
+		ConsecutiveFailures: 5,
+		FailureRatio:        0.5,
+		MinRequests:         20,
+		Window:              10 * time.Second,
+		Cooldown:            5 * time.Second,
+		HalfOpenRequests:    1,
+	}
+}
+
+// CircuitBreaker keeps one circuit per name, the host for HTTP and the target for gRPC
+type CircuitBreaker struct {
+	policy CircuitBreakerPolicy
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+	now      func() time.Time
+}
+
+type circuit struct {
+	state      CircuitState
+	generation uint64
+
+	consecutiveFailures int
+	requests            int
+	failures            int
+	windowStart         time.Time
+
+	openedAt          time.Time
+	halfOpenInFlight  int
+	halfOpenSuccesses int
+}
+
+func NewCircuitBreaker(policy CircuitBreakerPolicy) *CircuitBreaker {
+	if policy.HalfOpenRequests <= 0 {
+		policy.HalfOpenRequests = 1
+	}
+	return &CircuitBreaker{
+		policy:   policy,
+		circuits: make(map[string]*circuit),
+		now:      time.Now,
+	}
+}
+
+func (cb *CircuitBreaker) State(name string) CircuitState {
+	var change *stateChange
+	defer func() { cb.notify(change) }()
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.circuits[name]
+	if !ok {
+		return CircuitClosed
+	}
+	change = cb.refresh(name, c, cb.now())
+	return c.state
+}
+
+// allow reserves a call on the circuit, done must be called with the outcome when the call is allowed
+func (cb *CircuitBreaker) allow(name string) (done func(failed bool), err error) {
+	var change *stateChange
+	defer func() { cb.notify(change) }()
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.now()
+	c, ok := cb.circuits[name]
+	if !ok {
+		c = &circuit{windowStart: now}
+		cb.circuits[name] = c
+	}
+	change = cb.refresh(name, c, now)
+
+	switch c.state {
+	case CircuitOpen:
+		return nil, ErrCircuitOpen
+	case CircuitHalfOpen:
+		if c.halfOpenInFlight >= cb.policy.HalfOpenRequests {
+			return nil, ErrCircuitOpen
+		}
+		c.halfOpenInFlight++
+	}
+
+	generation := c.generation
+	return func(failed bool) {
+		cb.record(name, c, generation, failed)
+	}, nil
+}
+
+func (cb *CircuitBreaker) record(name string, c *circuit, generation uint64, failed bool) {
+	var change *stateChange
+	defer func() { cb.notify(change) }()
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	// outcomes of calls started before the last state change are not counted
+	if generation != c.generation {
+		return
+	}
+
+	now := cb.now()
+	switch c.state {
+	case CircuitHalfOpen:
+		c.halfOpenInFlight--
+		if failed {
+			change = cb.setState(name, c, CircuitOpen, now)
+			return
+		}
+		c.halfOpenSuccesses++
+		if c.halfOpenSuccesses >= cb.policy.HalfOpenRequests {
+			change = cb.setState(name, c, CircuitClosed, now)
+		}
+	case CircuitClosed:
+		c.requests++
+		if !failed {
+			c.consecutiveFailures = 0
+			return
+		}
+		c.failures++
+		c.consecutiveFailures++
+
+		if cb.shouldTrip(c) {
+			change = cb.setState(name, c, CircuitOpen, now)
+		}
+	}
+}
+
+func (cb *CircuitBreaker) shouldTrip(c *circuit) bool {
+	if cb.policy.ConsecutiveFailures > 0 && c.consecutiveFailures >= cb.policy.ConsecutiveFailures {
+		return true
+	}
+	if cb.policy.FailureRatio > 0 && c.requests >= cb.policy.MinRequests {
+		return float64(c.failures)/float64(c.requests) >= cb.policy.FailureRatio
+	}
+	return false
+}
+
+// refresh moves an open circuit to half-open after the cooldown and starts a new window for a closed one
+func (cb *CircuitBreaker) refresh(name string, c *circuit, now time.Time) *stateChange {
+	switch c.state {
+	case CircuitOpen:
+		if now.Sub(c.openedAt) >= cb.policy.Cooldown {
+			return cb.setState(name, c, CircuitHalfOpen, now)
+		}
+	case CircuitClosed:
+		if cb.policy.Window > 0 && now.Sub(c.windowStart) >= cb.policy.Window {
+			c.requests, c.failures = 0, 0
+			c.windowStart = now
+		}
+	}
+	return nil
+}
+
+type stateChange struct {
+	name string
+	from CircuitState
+	to   CircuitState
+}
+
+// setState must be called with cb.mu held, the returned change is passed to notify once the lock is released
+func (cb *CircuitBreaker) setState(name string, c *circuit, state CircuitState, now time.Time) *stateChange {
+	from := c.state
+
+	c.state = state
+	c.generation++
+	c.consecutiveFailures, c.requests, c.failures = 0, 0, 0
+	c.windowStart = now
+	c.halfOpenInFlight, c.halfOpenSuccesses = 0, 0
+	if state == CircuitOpen {
+		c.openedAt = now
+	}
+
+	if from == state {
+		return nil
+	}
+	return &stateChange{name: name, from: from, to: state}
+}
+
+// notify runs the hook outside the lock so that it can call State and a slow hook does not block other calls
+func (cb *CircuitBreaker) notify(change *stateChange) {
+	if change == nil || cb.policy.OnStateChange == nil {
+		return
+	}
+	cb.policy.OnStateChange(change.name, change.from, change.to)
+}
+
+// isHttpCircuitFailure counts server errors and transport errors, the cancellation by the caller is not a failure
+func isHttpCircuitFailure(res *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return res.StatusCode >= http.StatusInternalServerError
+}
+
+func isGrpcCircuitFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+func CircuitBreakerUnaryClientInterceptor(cb *CircuitBreaker) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		done, err := cb.allow(grpcTargetForLog(cc))
+		if err != nil {
+			return err
+		}
+
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		done(isGrpcCircuitFailure(err))
+		return err
+	}
+}
+
+// CircuitBreakerStreamClientInterceptor judges a stream only by the error of opening it
+func CircuitBreakerStreamClientInterceptor(cb *CircuitBreaker) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		done, err := cb.allow(grpcTargetForLog(cc))
+		if err != nil {
+			return nil, err
+		}
+
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		done(isGrpcCircuitFailure(err))
+		return stream, err
+	}
+}
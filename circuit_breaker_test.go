@@ -0,0 +1,217 @@
+package ginney
+
+import (
+	"context"
+	"fmt"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestCircuitBreaker(policy CircuitBreakerPolicy) (*CircuitBreaker, *fakeClock, *[]string) {
+	var changes []string
+	policy.OnStateChange = func(name string, from CircuitState, to CircuitState) {
+		changes = append(changes, fmt.Sprintf("%s:%s->%s", name, from, to))
+	}
+
+	clock := &fakeClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cb := NewCircuitBreaker(policy)
+	cb.now = clock.Now
+	return cb, clock, &changes
+}
+
+func callCircuit(cb *CircuitBreaker, name string, failed bool) error {
+	done, err := cb.allow(name)
+	if err != nil {
+		return err
+	}
+	done(failed)
+	return nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("Happy - trips on consecutive failures and recovers through half-open", func(t *testing.T) {
+		cb, clock, changes := newTestCircuitBreaker(CircuitBreakerPolicy{ConsecutiveFailures: 3, Cooldown: time.Second})
+
+		assert.NoError(t, callCircuit(cb, "a", true))
+		assert.NoError(t, callCircuit(cb, "a", false))
+		assert.NoError(t, callCircuit(cb, "a", true))
+		assert.NoError(t, callCircuit(cb, "a", true))
+		assert.Equal(t, CircuitClosed, cb.State("a"))
+
+		assert.NoError(t, callCircuit(cb, "a", true))
+		assert.Equal(t, CircuitOpen, cb.State("a"))
+		assert.Equal(t, ErrCircuitOpen, callCircuit(cb, "a", false))
+
+		// other hosts are not affected
+		assert.NoError(t, callCircuit(cb, "b", false))
+
+		clock.now = clock.now.Add(time.Second)
+		assert.Equal(t, CircuitHalfOpen, cb.State("a"))
+
+		done, err := cb.allow("a")
+		assert.NoError(t, err)
+		assert.Equal(t, ErrCircuitOpen, callCircuit(cb, "a", false), "only one probe at once")
+		done(false)
+
+		assert.Equal(t, CircuitClosed, cb.State("a"))
+		assert.Equal(t, []string{"a:closed->open", "a:open->half-open", "a:half-open->closed"}, *changes)
+	})
+
+	t.Run("Happy - failed probe opens the circuit again", func(t *testing.T) {
+		cb, clock, changes := newTestCircuitBreaker(CircuitBreakerPolicy{ConsecutiveFailures: 1, Cooldown: time.Second})
+
+		assert.NoError(t, callCircuit(cb, "a", true))
+		clock.now = clock.now.Add(time.Second)
+		assert.NoError(t, callCircuit(cb, "a", true))
+
+		assert.Equal(t, CircuitOpen, cb.State("a"))
+		assert.Equal(t, []string{"a:closed->open", "a:open->half-open", "a:half-open->open"}, *changes)
+	})
+
+	t.Run("Happy - state change hook can read the state", func(t *testing.T) {
+		var cb *CircuitBreaker
+		var observed []CircuitState
+		cb = NewCircuitBreaker(CircuitBreakerPolicy{
+			ConsecutiveFailures: 1,
+			Cooldown:            time.Hour,
+			OnStateChange: func(name string, from CircuitState, to CircuitState) {
+				observed = append(observed, cb.State(name))
+			},
+		})
+
+		finished := make(chan error)
+		go func() { finished <- callCircuit(cb, "a", true) }()
+
+		select {
+		case err := <-finished:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("the hook is called while the lock is held")
+		}
+		assert.Equal(t, []CircuitState{CircuitOpen}, observed)
+	})
+
+	t.Run("Happy - trips on failure ratio once min requests are seen", func(t *testing.T) {
+		cb, _, _ := newTestCircuitBreaker(CircuitBreakerPolicy{FailureRatio: 0.5, MinRequests: 4, Window: time.Minute, Cooldown: time.Second})
+
+		assert.NoError(t, callCircuit(cb, "a", true))
+		assert.NoError(t, callCircuit(cb, "a", false))
+		assert.NoError(t, callCircuit(cb, "a", false))
+		assert.Equal(t, CircuitClosed, cb.State("a"))
+
+		assert.NoError(t, callCircuit(cb, "a", true))
+		assert.Equal(t, CircuitOpen, cb.State("a"))
+	})
+
+	t.Run("Happy - failure ratio is counted per window", func(t *testing.T) {
+		cb, clock, _ := newTestCircuitBreaker(CircuitBreakerPolicy{FailureRatio: 0.5, MinRequests: 2, Window: time.Minute, Cooldown: time.Second})
+
+		assert.NoError(t, callCircuit(cb, "a", true))
+		clock.now = clock.now.Add(time.Minute)
+		assert.NoError(t, callCircuit(cb, "a", false))
+
+		assert.Equal(t, CircuitClosed, cb.State("a"))
+	})
+
+	t.Run("Happy - outcome of a call from a previous state is ignored", func(t *testing.T) {
+		cb, _, _ := newTestCircuitBreaker(CircuitBreakerPolicy{ConsecutiveFailures: 1, Cooldown: time.Minute})
+
+		slowDone, err := cb.allow("a")
+		assert.NoError(t, err)
+		assert.NoError(t, callCircuit(cb, "a", true))
+		slowDone(false)
+
+		assert.Equal(t, CircuitOpen, cb.State("a"))
+	})
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	t.Run("Happy - open circuit fails fast with 503 error", func(t *testing.T) {
+		calls := 0
+		httpmock.Activate()
+		httpmock.RegisterResponder(http.MethodGet, "https://www.fcuk.com/v1/ping", func(req *http.Request) (*http.Response, error) {
+			calls++
+			return httpmock.NewStringResponse(http.StatusInternalServerError, `{"status":"fail"}`), nil
+		})
+
+		cb := NewCircuitBreaker(CircuitBreakerPolicy{ConsecutiveFailures: 2, Cooldown: time.Minute})
+		client := NewClient(WithCircuitBreaker(cb))
+
+		for i := 0; i < 2; i++ {
+			resp, err := client.Get(context.TODO(), "https://www.fcuk.com/v1/ping")
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		}
+
+		_, err := client.Get(context.TODO(), "https://www.fcuk.com/v1/ping")
+		assert.Equal(t, ErrCircuitOpen, err)
+		assert.Equal(t, http.StatusServiceUnavailable, AsError(err).HttpStatus)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, CircuitOpen, cb.State("www.fcuk.com"))
+	})
+
+	t.Run("Happy - client errors and cancellation do not trip the circuit", func(t *testing.T) {
+		httpmock.Activate()
+		httpmock.RegisterResponder(http.MethodGet, "https://www.fcuk.com/v1/ping", httpmock.NewStringResponder(http.StatusNotFound, `{"status":"fail"}`))
+		httpmock.RegisterResponder(http.MethodGet, "https://www.fcuk.com/v1/slow", httpmock.NewErrorResponder(context.Canceled))
+
+		cb := NewCircuitBreaker(CircuitBreakerPolicy{ConsecutiveFailures: 1, Cooldown: time.Minute})
+		client := NewClient(WithCircuitBreaker(cb))
+
+		_, err := client.Get(context.TODO(), "https://www.fcuk.com/v1/ping")
+		assert.NoError(t, err)
+
+		_, err = client.Get(context.TODO(), "https://www.fcuk.com/v1/slow")
+		assert.Error(t, err)
+
+		assert.Equal(t, CircuitClosed, cb.State("www.fcuk.com"))
+	})
+}
+
+func TestCircuitBreakerUnaryClientInterceptor(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerPolicy{ConsecutiveFailures: 1, Cooldown: time.Minute})
+	interceptor := CircuitBreakerUnaryClientInterceptor(cb)
+
+	calls := 0
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		return status.Error(codes.Unavailable, "connection refused")
+	}
+
+	err := interceptor(context.TODO(), "randomMethod", nil, nil, nil, invoker)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	err = interceptor(context.TODO(), "randomMethod", nil, nil, nil, invoker)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, ErrorCodeCircuitOpen, AsError(err).Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestCircuitBreakerStreamClientInterceptor(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerPolicy{ConsecutiveFailures: 1, Cooldown: time.Minute})
+	interceptor := CircuitBreakerStreamClientInterceptor(cb)
+
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, status.Error(codes.InvalidArgument, "bad request")
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := interceptor(context.TODO(), &grpc.StreamDesc{}, nil, "randomStreamMethod", streamer)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+	assert.Equal(t, CircuitClosed, cb.State("-"))
+}
@@ -0,0 +1,239 @@
+package ginney
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	DefaultClient = NewClient()
+
+	// DefaultForwardedHeaders is a common set for WithForwardedHeaders
+	DefaultForwardedHeaders = []string{"Authorization", "Accept-Language", "X-Forwarded-For"}
+)
+
+type Client struct {
+	baseUrl     string
+	httpClient  *http.Client
+	transport   http.RoundTripper
+	retryPolicy RetryPolicy
+	logOut      io.Writer
+	logOptions  []LogOption
+	tracer      ClientTracer
+	metrics     *ClientMetrics
+
+	forwardedHeaders []string
+	circuitBreaker   *CircuitBreaker
+}
+
+type ClientOption func(client *Client)
+
+// ClientTracer creates a span around one outbound request including its retries, the otelginney package
+// provides one for OpenTelemetry so that the tracing dependency stays out of this package
+type ClientTracer interface {
+	// StartClientSpan returns the context of the span and a function ending the span with the outcome
+	StartClientSpan(ctx context.Context, method string, url string) (context.Context, func(res *http.Response, err error))
+	// Inject writes the span context of ctx into the headers of every attempt
+	Inject(ctx context.Context, header http.Header)
+}
+
+func NewClient(opts ...ClientOption) *Client {
+	client := &Client{
+		httpClient: &http.Client{},
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	// wrapping the transport once all options are applied, so the order of options doesn't matter
+	transport := client.transport
+	if client.logOut != nil {
+		transport = NewLogRoundTripper(client.logOut, transport, client.logOptions...)
+	}
+	client.httpClient.Transport = transport
+
+	return client
+}
+
+func WithBaseUrl(baseUrl string) ClientOption {
+	return func(client *Client) {
+		client.baseUrl = strings.TrimRight(baseUrl, "/")
+	}
+}
+
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(client *Client) {
+		client.httpClient.Timeout = timeout
+	}
+}
+
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(client *Client) {
+		client.transport = transport
+	}
+}
+
+func WithLogging(out io.Writer, opts ...LogOption) ClientOption {
+	return func(client *Client) {
+		client.logOut = out
+		client.logOptions = opts
+	}
+}
+
+func WithTracing(tracer ClientTracer) ClientOption {
+	return func(client *Client) {
+		client.tracer = tracer
+	}
+}
+
+func WithMetrics(metrics *ClientMetrics) ClientOption {
+	return func(client *Client) {
+		client.metrics = metrics
+	}
+}
+
+func WithRetryPolicy(retryPolicy RetryPolicy) ClientOption {
+	return func(client *Client) {
+		client.retryPolicy = retryPolicy
+	}
+}
+
+// WithCircuitBreaker stops calling a host while its circuit is open, the calls fail fast with ErrCircuitOpen
+func WithCircuitBreaker(circuitBreaker *CircuitBreaker) ClientOption {
+	return func(client *Client) {
+		client.circuitBreaker = circuitBreaker
+	}
+}
+
+// WithForwardedHeaders copies the headers from the inbound gin request unless the request sets them itself
+func WithForwardedHeaders(headerNames ...string) ClientOption {
+	return func(client *Client) {
+		client.forwardedHeaders = append(client.forwardedHeaders, headerNames...)
+	}
+}
+
+func (c *Client) forwardHeaders(ginContext *gin.Context, header http.Header) {
+	if ginContext.Request == nil {
+		return
+	}
+	for _, headerName := range c.forwardedHeaders {
+		if header.Get(headerName) != "" {
+			continue
+		}
+		for _, value := range ginContext.Request.Header.Values(headerName) {
+			header.Add(headerName, value)
+		}
+	}
+}
+
+func (c *Client) resolveUrl(url string) string {
+	if c.baseUrl == "" || strings.Contains(url, "://") {
+		return url
+	}
+	return c.baseUrl + "/" + strings.TrimLeft(url, "/")
+}
+
+type RetryPolicy struct {
+	MaxRetries           int
+	InitialBackoff       time.Duration
+	MaxBackoff           time.Duration
+	Multiplier           float64
+	Jitter               float64
+	RetryableStatusCodes []int
+	RetryNonIdempotent   bool
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func (p RetryPolicy) allowsMethod(method string) bool {
+	if p.MaxRetries <= 0 {
+		return false
+	}
+	return p.RetryNonIdempotent || isIdempotentMethod(method)
+}
+
+func (p RetryPolicy) shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	for _, statusCode := range p.RetryableStatusCodes {
+		if statusCode == res.StatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		backoff += backoff * p.Jitter * (2*randomFloat64() - 1)
+	}
+	if backoff < 0 {
+		return 0
+	}
+	return time.Duration(backoff)
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func sleepWithContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+var (
+	randomMutex  sync.Mutex
+	randomSource = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func randomFloat64() float64 {
+	randomMutex.Lock()
+	defer randomMutex.Unlock()
+	return randomSource.Float64()
+}
@@ -0,0 +1,183 @@
+package ginney
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+func CorrelationIdUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withOutgoingTraceContext(withOutgoingCorrelationId(ctx)), method, req, reply, cc, opts...)
+	}
+}
+
+func CorrelationIdStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withOutgoingTraceContext(withOutgoingCorrelationId(ctx)), desc, cc, method, opts...)
+	}
+}
+
+func LogWithCorrelationIdUnaryClientInterceptor(out io.Writer, ignoreList []string, opts ...LogOption) grpc.UnaryClientInterceptor {
+	mustIgnoreLogging := newGrpcIgnoreList(ignoreList)
+	config := newLogConfig(opts)
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+
+		invokerErr := invoker(ctx, method, req, reply, cc, opts...)
+		if mustIgnoreLogging(method) {
+			return invokerErr
+		}
+
+		// Logging function
+		go func() {
+			// end
+			end := time.Now()
+
+			// latency
+			latency := end.Sub(start)
+
+			_, _ = fmt.Fprint(out, config.formatter.Format(LogRecord{
+				Time:          end,
+				CorrelationId: grpcOutgoingCorrelationIdForLog(ctx),
+				Status:        grpcStatusCodeForLog(invokerErr),
+				Latency:       latency,
+				ClientIp:      grpcTargetForLog(cc),
+				Path:          method,
+				Body:          grpcRequestBodyToString(req),
+			}))
+		}()
+
+		return invokerErr
+	}
+}
+
+func LogWithCorrelationIdStreamClientInterceptor(out io.Writer, ignoreList []string, opts ...LogOption) grpc.StreamClientInterceptor {
+	mustIgnoreLogging := newGrpcIgnoreList(ignoreList)
+	config := newLogConfig(opts)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+
+		logStream := func(streamErr error, received int64, sent int64) {
+			// end
+			end := time.Now()
+
+			// latency
+			latency := end.Sub(start)
+
+			_, _ = fmt.Fprint(out, config.formatter.Format(LogRecord{
+				Time:          end,
+				CorrelationId: grpcOutgoingCorrelationIdForLog(ctx),
+				Status:        grpcStatusCodeForLog(streamErr),
+				Latency:       latency,
+				ClientIp:      grpcTargetForLog(cc),
+				Path:          method,
+				Body:          grpcStreamSummaryToString(received, sent),
+			}))
+		}
+
+		cs, streamerErr := streamer(ctx, desc, cc, method, opts...)
+		if mustIgnoreLogging(method) {
+			return cs, streamerErr
+		}
+		if streamerErr != nil {
+			go logStream(streamerErr, 0, 0)
+			return cs, streamerErr
+		}
+
+		stream := &loggingClientStream{ClientStream: cs, desc: desc, onFinish: logStream, finished: make(chan struct{})}
+		go stream.watch(ctx)
+		return stream, nil
+	}
+}
+
+type loggingClientStream struct {
+	grpc.ClientStream
+	desc          *grpc.StreamDesc
+	receivedCount int64
+	sentCount     int64
+	once          sync.Once
+	finished      chan struct{}
+	onFinish      func(streamErr error, received int64, sent int64)
+}
+
+func (s *loggingClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		atomic.AddInt64(&s.sentCount, 1)
+	} else if err != io.EOF {
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *loggingClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		atomic.AddInt64(&s.receivedCount, 1)
+		// unary and client-streaming calls receive a single response, CloseAndRecv does not read until io.EOF
+		if !s.desc.ServerStreams {
+			s.finish(nil)
+		}
+	case err == io.EOF:
+		s.finish(nil)
+	default:
+		s.finish(err)
+	}
+	return err
+}
+
+// watch logs a stream the caller gives up on by cancelling ctx instead of reading it to the end
+func (s *loggingClientStream) watch(ctx context.Context) {
+	select {
+	case <-s.Context().Done():
+		// the stream context is also done when the stream completes, that outcome is logged by RecvMsg
+		if err := ctx.Err(); err != nil {
+			s.finish(status.FromContextError(err).Err())
+		}
+	case <-s.finished:
+	}
+}
+
+func (s *loggingClientStream) finish(streamErr error) {
+	s.once.Do(func() {
+		close(s.finished)
+		go s.onFinish(streamErr, atomic.LoadInt64(&s.receivedCount), atomic.LoadInt64(&s.sentCount))
+	})
+}
+
+func withOutgoingCorrelationId(ctx context.Context) context.Context {
+	if meta, ok := metadata.FromOutgoingContext(ctx); ok && len(meta.Get(CorrelationIdHeaderKey)) > 0 {
+		return ctx
+	}
+
+	correlationId := CorrelationIdFromContext(ctx)
+	if correlationId == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, CorrelationIdHeaderKey, correlationId)
+}
+
+func grpcOutgoingCorrelationIdForLog(ctx context.Context) string {
+	correlationId := CorrelationIdFromContext(ctx)
+	if correlationId == "" {
+		return "-"
+	}
+	return correlationId
+}
+
+func grpcTargetForLog(cc *grpc.ClientConn) string {
+	if cc == nil {
+		return "-"
+	}
+	return cc.Target()
+}
@@ -0,0 +1,198 @@
+package ginney
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCorrelationIdUnaryClientInterceptor(t *testing.T) {
+	req := map[string]interface{}{"id": "1"}
+
+	invokeAndGetMetadata := func(ctx context.Context) metadata.MD {
+		var outgoing metadata.MD
+		interceptor := CorrelationIdUnaryClientInterceptor()
+		err := interceptor(ctx, "randomMethod", req, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			outgoing, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		})
+		assert.NoError(t, err)
+		return outgoing
+	}
+
+	t.Run("Happy - correlation id from gin context", func(t *testing.T) {
+		gc := createGinContextWithCorrelationId(http.MethodGet, "/chaiyawatkit", "random-uuid")
+		ctx := FromGinContextToContext(gc)
+
+		outgoing := invokeAndGetMetadata(ctx)
+		assert.Equal(t, []string{"random-uuid"}, outgoing.Get(CorrelationIdHeaderKey))
+	})
+
+	t.Run("Happy - correlation id from incoming metadata", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CorrelationIdHeaderKey, "random-uuid"))
+
+		outgoing := invokeAndGetMetadata(ctx)
+		assert.Equal(t, []string{"random-uuid"}, outgoing.Get(CorrelationIdHeaderKey))
+	})
+
+	t.Run("Happy - correlation id is already in outgoing metadata", func(t *testing.T) {
+		gc := createGinContextWithCorrelationId(http.MethodGet, "/chaiyawatkit", "random-uuid")
+		ctx := FromContextToGrpcOutgoingContext(FromGinContextToContext(gc))
+
+		outgoing := invokeAndGetMetadata(ctx)
+		assert.Equal(t, []string{"random-uuid"}, outgoing.Get(CorrelationIdHeaderKey))
+	})
+
+	t.Run("Happy - no correlation id at all", func(t *testing.T) {
+		outgoing := invokeAndGetMetadata(context.TODO())
+		assert.Len(t, outgoing.Get(CorrelationIdHeaderKey), 0)
+	})
+}
+
+func TestCorrelationIdStreamClientInterceptor(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		gc := createGinContextWithCorrelationId(http.MethodGet, "/chaiyawatkit", "random-uuid")
+		ctx := FromGinContextToContext(gc)
+
+		var outgoing metadata.MD
+		interceptor := CorrelationIdStreamClientInterceptor()
+		_, err := interceptor(ctx, &grpc.StreamDesc{}, nil, "randomStreamMethod", func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			outgoing, _ = metadata.FromOutgoingContext(ctx)
+			return newFakeClientStream(ctx), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"random-uuid"}, outgoing.Get(CorrelationIdHeaderKey))
+	})
+}
+
+func TestLogWithCorrelationIdUnaryClientInterceptor(t *testing.T) {
+	req := map[string]interface{}{"id": "1", "password": "secret"}
+
+	t.Run("Happy", func(t *testing.T) {
+		buffer := new(syncBuffer)
+
+		ctx := metadata.AppendToOutgoingContext(context.TODO(), CorrelationIdHeaderKey, "random-uuid")
+		interceptor := LogWithCorrelationIdUnaryClientInterceptor(buffer, nil)
+		err := interceptor(ctx, "randomMethod", req, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return status.Error(codes.NotFound, "")
+		})
+		assert.Error(t, err)
+
+		// Wait for go routine in log
+		time.Sleep(1 * time.Second)
+
+		correlationId, statusCode, apiName, payload := extractLogMessage(buffer.String())
+		assert.Equal(t, codes.NotFound.String(), statusCode)
+		assert.Equal(t, "randomMethod", apiName)
+		assert.Equal(t, `{"id":"1","password":"[HIDDEN_FIELD]"}`+"\n", payload)
+		assert.Equal(t, "random-uuid", correlationId)
+	})
+
+	t.Run("Happy, no log when api name is in ignore list", func(t *testing.T) {
+		buffer := new(syncBuffer)
+
+		interceptor := LogWithCorrelationIdUnaryClientInterceptor(buffer, []string{"randomMethod"})
+		err := interceptor(context.TODO(), "randomMethod", req, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return nil
+		})
+		assert.NoError(t, err)
+
+		// Wait for go routine in log
+		time.Sleep(1 * time.Second)
+
+		assert.Equal(t, "", buffer.String())
+	})
+}
+
+func TestLogWithCorrelationIdStreamClientInterceptor(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		buffer := new(syncBuffer)
+
+		ctx := metadata.AppendToOutgoingContext(context.TODO(), CorrelationIdHeaderKey, "random-uuid")
+		interceptor := LogWithCorrelationIdStreamClientInterceptor(buffer, nil)
+		cs, err := interceptor(ctx, &grpc.StreamDesc{ServerStreams: true}, nil, "randomStreamMethod", func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return newFakeClientStream(ctx, "pong", "pong"), nil
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, cs.SendMsg("ping"))
+		for cs.RecvMsg(nil) == nil {
+		}
+
+		// Wait for go routine in log
+		time.Sleep(1 * time.Second)
+
+		correlationId, statusCode, apiName, payload := extractLogMessage(buffer.String())
+		assert.Equal(t, codes.OK.String(), statusCode)
+		assert.Equal(t, "randomStreamMethod", apiName)
+		assert.Equal(t, `{"received":2,"sent":1}`+"\n", payload)
+		assert.Equal(t, "random-uuid", correlationId)
+	})
+
+	t.Run("Happy, client streaming is logged after the single response", func(t *testing.T) {
+		buffer := new(syncBuffer)
+
+		interceptor := LogWithCorrelationIdStreamClientInterceptor(buffer, nil)
+		cs, err := interceptor(context.TODO(), &grpc.StreamDesc{ClientStreams: true}, nil, "randomStreamMethod", func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return newFakeClientStream(ctx, "pong"), nil
+		})
+		assert.NoError(t, err)
+
+		// what the generated CloseAndRecv does after sending the requests
+		assert.NoError(t, cs.SendMsg("ping"))
+		assert.NoError(t, cs.SendMsg("ping"))
+		assert.NoError(t, cs.RecvMsg(nil))
+
+		// Wait for go routine in log
+		time.Sleep(1 * time.Second)
+
+		_, statusCode, apiName, payload := extractLogMessage(buffer.String())
+		assert.Equal(t, codes.OK.String(), statusCode)
+		assert.Equal(t, "randomStreamMethod", apiName)
+		assert.Equal(t, `{"received":1,"sent":2}`+"\n", payload)
+	})
+
+	t.Run("Happy, caller cancels the stream before reading it to the end", func(t *testing.T) {
+		buffer := new(syncBuffer)
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		interceptor := LogWithCorrelationIdStreamClientInterceptor(buffer, nil)
+		cs, err := interceptor(ctx, &grpc.StreamDesc{ServerStreams: true}, nil, "randomStreamMethod", func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return newFakeClientStream(ctx, "pong", "pong"), nil
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, cs.RecvMsg(nil))
+		cancel()
+
+		// Wait for go routine in log
+		time.Sleep(1 * time.Second)
+
+		_, statusCode, _, payload := extractLogMessage(buffer.String())
+		assert.Equal(t, codes.Canceled.String(), statusCode)
+		assert.Equal(t, `{"received":1,"sent":0}`+"\n", payload)
+	})
+
+	t.Run("Happy, streamer returns error", func(t *testing.T) {
+		buffer := new(syncBuffer)
+
+		interceptor := LogWithCorrelationIdStreamClientInterceptor(buffer, nil)
+		_, err := interceptor(context.TODO(), &grpc.StreamDesc{}, nil, "randomStreamMethod", func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return nil, status.Error(codes.Unavailable, "")
+		})
+		assert.Error(t, err)
+
+		// Wait for go routine in log
+		time.Sleep(1 * time.Second)
+
+		correlationId, statusCode, _, _ := extractLogMessage(buffer.String())
+		assert.Equal(t, codes.Unavailable.String(), statusCode)
+		assert.Equal(t, "-", correlationId)
+	})
+}
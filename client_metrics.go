@@ -0,0 +1,141 @@
+package ginney
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	unnamedOperationLabel = "unnamed"
+)
+
+const (
+	ErrorClassTimeout     = "timeout"
+	ErrorClassCanceled    = "canceled"
+	ErrorClassConnection  = "connection"
+	ErrorClassClientError = "client_error"
+	ErrorClassServerError = "server_error"
+	ErrorClassCircuitOpen = "circuit_open"
+)
+
+type ClientMetrics struct {
+	requestsTotal   *prometheus.CounterVec
+	errorsTotal     *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	retriesTotal    *prometheus.CounterVec
+}
+
+func NewClientMetrics(opts ...MetricsOption) *ClientMetrics {
+	config := &metricsConfig{
+		durationBuckets: prometheus.DefBuckets,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	var registerer prometheus.Registerer = prometheus.DefaultRegisterer
+	if config.registry != nil {
+		registerer = config.registry
+	}
+
+	m := &ClientMetrics{
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.namespace,
+			Name:      "http_client_requests_total",
+			Help:      "Total number of outbound HTTP requests by target host, operation, method and status code.",
+		}, []string{"host", "operation", "method", "status"}),
+		errorsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.namespace,
+			Name:      "http_client_errors_total",
+			Help:      "Total number of failed outbound HTTP requests by target host, operation and error class.",
+		}, []string{"host", "operation", "class"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.namespace,
+			Name:      "http_client_request_duration_seconds",
+			Help:      "Duration of outbound HTTP requests including retries by target host, operation and method.",
+			Buckets:   config.durationBuckets,
+		}, []string{"host", "operation", "method"}),
+		retriesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.namespace,
+			Name:      "http_client_retries_total",
+			Help:      "Total number of outbound HTTP request retries by target host and operation.",
+		}, []string{"host", "operation"}),
+	}
+
+	registerer.MustRegister(m.requestsTotal, m.errorsTotal, m.requestDuration, m.retriesTotal)
+
+	return m
+}
+
+func ContextWithOperationName(ctx context.Context, operationName string) context.Context {
+	return context.WithValue(ctx, OperationNameKey, operationName)
+}
+
+func operationNameFromContext(ctx context.Context) string {
+	if operationName, ok := ctx.Value(OperationNameKey).(string); ok && operationName != "" {
+		return operationName
+	}
+	return unnamedOperationLabel
+}
+
+func (m *ClientMetrics) observe(ctx context.Context, method string, url string, res *http.Response, err error, latency time.Duration) {
+	host := hostFromUrl(url)
+	operation := operationNameFromContext(ctx)
+
+	statusCode := "-"
+	if err == nil {
+		statusCode = strconv.Itoa(res.StatusCode)
+	}
+
+	m.requestsTotal.WithLabelValues(host, operation, method, statusCode).Inc()
+	m.requestDuration.WithLabelValues(host, operation, method).Observe(latency.Seconds())
+
+	if errorClass := classifyHttpError(res, err); errorClass != "" {
+		m.errorsTotal.WithLabelValues(host, operation, errorClass).Inc()
+	}
+}
+
+func (m *ClientMetrics) observeRetry(ctx context.Context, url string) {
+	m.retriesTotal.WithLabelValues(hostFromUrl(url), operationNameFromContext(ctx)).Inc()
+}
+
+func classifyHttpError(res *http.Response, err error) string {
+	if err != nil {
+		var netErr net.Error
+		switch {
+		case errors.Is(err, ErrCircuitOpen):
+			return ErrorClassCircuitOpen
+		case errors.Is(err, context.Canceled):
+			return ErrorClassCanceled
+		case errors.Is(err, context.DeadlineExceeded):
+			return ErrorClassTimeout
+		case errors.As(err, &netErr) && netErr.Timeout():
+			return ErrorClassTimeout
+		default:
+			return ErrorClassConnection
+		}
+	}
+
+	switch {
+	case res.StatusCode >= http.StatusInternalServerError:
+		return ErrorClassServerError
+	case res.StatusCode >= http.StatusBadRequest:
+		return ErrorClassClientError
+	default:
+		return ""
+	}
+}
+
+func hostFromUrl(url string) string {
+	parsedUrl, err := neturl.Parse(url)
+	if err != nil || parsedUrl.Host == "" {
+		return "-"
+	}
+	return parsedUrl.Host
+}
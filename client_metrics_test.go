@@ -0,0 +1,60 @@
+package ginney
+
+import (
+	"context"
+	"errors"
+	"github.com/jarcoal/httpmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClientMetrics(t *testing.T) {
+	t.Run("Happy - requests, errors and retries are measured per host and operation", func(t *testing.T) {
+		httpmock.Activate()
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodGet, "https://stellar.fcuk.com/v1/accounts", httpmock.NewStringResponder(http.StatusServiceUnavailable, `{"status": "fail"}`))
+
+		metrics := NewClientMetrics(WithMetricsRegistry(prometheus.NewRegistry()))
+		retryPolicy := DefaultRetryPolicy()
+		retryPolicy.MaxRetries = 2
+		retryPolicy.InitialBackoff = time.Millisecond
+		client := NewClient(WithMetrics(metrics), WithRetryPolicy(retryPolicy))
+
+		ctx := ContextWithOperationName(context.TODO(), "stellar.accounts")
+		_, err := client.Get(ctx, "https://stellar.fcuk.com/v1/accounts")
+		assert.NoError(t, err)
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requestsTotal.WithLabelValues("stellar.fcuk.com", "stellar.accounts", http.MethodGet, "503")))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.errorsTotal.WithLabelValues("stellar.fcuk.com", "stellar.accounts", ErrorClassServerError)))
+		assert.Equal(t, float64(2), testutil.ToFloat64(metrics.retriesTotal.WithLabelValues("stellar.fcuk.com", "stellar.accounts")))
+		assert.Equal(t, 1, testutil.CollectAndCount(metrics.requestDuration))
+	})
+
+	t.Run("Happy - connection error without operation name", func(t *testing.T) {
+		httpmock.Activate()
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodPost, "https://stellar.fcuk.com/v1/sign", httpmock.NewErrorResponder(errors.New("connection refused")))
+
+		metrics := NewClientMetrics(WithMetricsRegistry(prometheus.NewRegistry()))
+		client := NewClient(WithMetrics(metrics))
+
+		_, err := client.Post(context.TODO(), "https://stellar.fcuk.com/v1/sign", "application/json", nil)
+		assert.Error(t, err)
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requestsTotal.WithLabelValues("stellar.fcuk.com", unnamedOperationLabel, http.MethodPost, "-")))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.errorsTotal.WithLabelValues("stellar.fcuk.com", unnamedOperationLabel, ErrorClassConnection)))
+	})
+}
+
+func TestClassifyHttpError(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		assert.Equal(t, ErrorClassCanceled, classifyHttpError(nil, context.Canceled))
+		assert.Equal(t, ErrorClassTimeout, classifyHttpError(nil, context.DeadlineExceeded))
+		assert.Equal(t, ErrorClassClientError, classifyHttpError(&http.Response{StatusCode: http.StatusNotFound}, nil))
+		assert.Equal(t, "", classifyHttpError(&http.Response{StatusCode: http.StatusOK}, nil))
+	})
+}
@@ -0,0 +1,301 @@
+package ginney
+
+import (
+	"bytes"
+	"context"
+	"github.com/jarcoal/httpmock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Run("Happy - base url is prepended to relative urls", func(t *testing.T) {
+		initHttpMock(http.MethodGet, "https://www.fcuk.com/v1/ping", http.StatusOK, `{"ping": "pong"}`)
+
+		client := NewClient(WithBaseUrl("https://www.fcuk.com/"), WithTimeout(time.Second))
+
+		resp, err := client.Get(context.TODO(), "/v1/ping")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Happy - absolute urls are not touched by base url", func(t *testing.T) {
+		initHttpMock(http.MethodGet, "https://www.fcuk.com/v1/ping", http.StatusOK, `{"ping": "pong"}`)
+
+		client := NewClient(WithBaseUrl("https://www.another.com"))
+
+		resp, err := client.Get(context.TODO(), "https://www.fcuk.com/v1/ping")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestClient_Retry(t *testing.T) {
+	retryPolicy := DefaultRetryPolicy()
+	retryPolicy.InitialBackoff = time.Millisecond
+	retryPolicy.MaxBackoff = 5 * time.Millisecond
+
+	newFlakyResponder := func(failures int, bodies *[]string) httpmock.Responder {
+		calls := 0
+		return func(req *http.Request) (*http.Response, error) {
+			calls++
+			if req.Body != nil {
+				body, _ := ioutil.ReadAll(req.Body)
+				*bodies = append(*bodies, string(body))
+			}
+			if calls <= failures {
+				return httpmock.NewStringResponse(http.StatusServiceUnavailable, `{"status": "fail"}`), nil
+			}
+			return httpmock.NewStringResponse(http.StatusOK, `{"ping": "pong"}`), nil
+		}
+	}
+
+	t.Run("Happy - idempotent request is retried until success", func(t *testing.T) {
+		httpmock.Activate()
+		httpmock.Reset()
+		var bodies []string
+		httpmock.RegisterResponder(http.MethodPut, "https://www.fcuk.com", newFlakyResponder(2, &bodies))
+
+		client := NewClient(WithRetryPolicy(retryPolicy))
+
+		resp, err := client.Put(context.TODO(), "https://www.fcuk.com", "application/json", bytes.NewBufferString(`{"example":"hello"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 3, httpmock.GetTotalCallCount())
+		assert.Equal(t, []string{`{"example":"hello"}`, `{"example":"hello"}`, `{"example":"hello"}`}, bodies)
+	})
+
+	t.Run("Happy - last response is returned when retries are exhausted", func(t *testing.T) {
+		httpmock.Activate()
+		httpmock.Reset()
+		var bodies []string
+		httpmock.RegisterResponder(http.MethodGet, "https://www.fcuk.com", newFlakyResponder(10, &bodies))
+
+		client := NewClient(WithRetryPolicy(retryPolicy))
+
+		resp, err := client.Get(context.TODO(), "https://www.fcuk.com")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, retryPolicy.MaxRetries+1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("Happy - non idempotent request is not retried by default", func(t *testing.T) {
+		httpmock.Activate()
+		httpmock.Reset()
+		var bodies []string
+		httpmock.RegisterResponder(http.MethodPost, "https://www.fcuk.com", newFlakyResponder(1, &bodies))
+
+		client := NewClient(WithRetryPolicy(retryPolicy))
+
+		resp, err := client.Post(context.TODO(), "https://www.fcuk.com", "application/json", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("Happy - non idempotent request is retried when it is allowed", func(t *testing.T) {
+		httpmock.Activate()
+		httpmock.Reset()
+		var bodies []string
+		httpmock.RegisterResponder(http.MethodPatch, "https://www.fcuk.com", newFlakyResponder(1, &bodies))
+
+		nonIdempotentRetryPolicy := retryPolicy
+		nonIdempotentRetryPolicy.RetryNonIdempotent = true
+		client := NewClient(WithRetryPolicy(nonIdempotentRetryPolicy))
+
+		resp, err := client.Patch(context.TODO(), "https://www.fcuk.com", "application/json", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 2, httpmock.GetTotalCallCount())
+	})
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Run("Happy - backoff grows exponentially and is capped", func(t *testing.T) {
+		retryPolicy := RetryPolicy{
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     300 * time.Millisecond,
+			Multiplier:     2,
+		}
+
+		assert.Equal(t, 100*time.Millisecond, retryPolicy.backoff(0))
+		assert.Equal(t, 200*time.Millisecond, retryPolicy.backoff(1))
+		assert.Equal(t, 300*time.Millisecond, retryPolicy.backoff(2))
+	})
+
+	t.Run("Happy - jitter keeps backoff in range", func(t *testing.T) {
+		retryPolicy := RetryPolicy{
+			InitialBackoff: 100 * time.Millisecond,
+			Multiplier:     2,
+			Jitter:         0.5,
+		}
+
+		for i := 0; i < 100; i++ {
+			backoff := retryPolicy.backoff(1)
+			assert.True(t, backoff >= 100*time.Millisecond && backoff <= 300*time.Millisecond)
+		}
+	})
+}
+
+func TestClient_Request(t *testing.T) {
+	var captured *http.Request
+	initCapturingHttpMock := func(method string) {
+		httpmock.Activate()
+		httpmock.RegisterResponder(method, `=~^https://www\.fcuk\.com/v1/accounts`, func(req *http.Request) (*http.Response, error) {
+			captured = req
+			return httpmock.NewStringResponse(http.StatusOK, `{"status":"success"}`), nil
+		})
+	}
+
+	t.Run("Happy - any method with per-request headers and query", func(t *testing.T) {
+		initCapturingHttpMock("OPTIONS")
+
+		resp, err := Request(context.TODO(), "OPTIONS", "https://www.fcuk.com/v1/accounts?a=1", nil,
+			WithHeader("Idempotency-Key", "key-1"),
+			WithHeaders(http.Header{"Authorization": []string{"Bearer token"}}),
+			WithQuery(map[string]string{"b": "2"}),
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "key-1", captured.Header.Get("Idempotency-Key"))
+		assert.Equal(t, "Bearer token", captured.Header.Get("Authorization"))
+		assert.Equal(t, "a=1&b=2", captured.URL.RawQuery)
+	})
+
+	t.Run("Happy - Patch and Head", func(t *testing.T) {
+		initCapturingHttpMock(http.MethodPatch)
+
+		_, err := Patch(context.TODO(), "https://www.fcuk.com/v1/accounts/1", "application/merge-patch+json", bytes.NewBufferString(`{"name":"a"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, http.MethodPatch, captured.Method)
+		assert.Equal(t, "application/merge-patch+json", captured.Header.Get(ContentTypeHeaderKey))
+
+		initCapturingHttpMock(http.MethodHead)
+
+		_, err = Head(context.TODO(), "https://www.fcuk.com/v1/accounts/1")
+		assert.NoError(t, err)
+		assert.Equal(t, http.MethodHead, captured.Method)
+	})
+
+	t.Run("Happy - Do keeps the headers of the request and adds the correlation id", func(t *testing.T) {
+		initCapturingHttpMock(http.MethodPut)
+
+		gc := createGinContextWithCorrelationId(http.MethodGet, "/chaiyawatkit", "random-uuid")
+		ctx := context.WithValue(context.TODO(), GinContextKey, gc)
+
+		req, _ := http.NewRequest(http.MethodPut, "https://www.fcuk.com/v1/accounts/1", bytes.NewBufferString(`{"name":"a"}`))
+		req.Header.Set("If-Match", "v1")
+
+		req.Host = "accounts.internal"
+
+		_, err := NewClient().Do(ctx, req)
+		assert.NoError(t, err)
+
+		assert.Equal(t, int64(len(`{"name":"a"}`)), captured.ContentLength)
+		assert.NotNil(t, captured.GetBody)
+		assert.Equal(t, "accounts.internal", captured.Host)
+		body, _ := ioutil.ReadAll(captured.Body)
+		assert.Equal(t, `{"name":"a"}`, string(body))
+		assert.Equal(t, "v1", captured.Header.Get("If-Match"))
+		assert.Equal(t, "random-uuid", captured.Header.Get(CorrelationIdHeaderKey))
+	})
+
+	t.Run("Happy - inbound headers are forwarded unless set on the request", func(t *testing.T) {
+		initCapturingHttpMock(http.MethodGet)
+
+		gc := createGinContextWithCorrelationId(http.MethodGet, "/chaiyawatkit", "random-uuid")
+		gc.Request.Header.Set("Authorization", "Bearer inbound")
+		gc.Request.Header.Set("Accept-Language", "th")
+		gc.Request.Header.Add("X-Forwarded-For", "10.0.0.1")
+		gc.Request.Header.Add("X-Forwarded-For", "10.0.0.2")
+		gc.Request.Header.Set("Cookie", "session=secret")
+		ctx := context.WithValue(context.TODO(), GinContextKey, gc)
+
+		client := NewClient(WithForwardedHeaders(DefaultForwardedHeaders...))
+		_, err := client.Get(ctx, "https://www.fcuk.com/v1/accounts", WithHeader("Accept-Language", "en"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer inbound", captured.Header.Get("Authorization"))
+		assert.Equal(t, "en", captured.Header.Get("Accept-Language"))
+		assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, captured.Header.Values("X-Forwarded-For"))
+		assert.Equal(t, "", captured.Header.Get("Cookie"))
+	})
+
+	t.Run("Error - invalid query", func(t *testing.T) {
+		_, err := Get(context.TODO(), "https://www.fcuk.com/v1/accounts", WithQuery(1))
+
+		assert.EqualError(t, err, "unsupported query type int")
+	})
+}
+
+func TestClient_Deadline(t *testing.T) {
+	t.Run("Happy - remaining deadline is sent downstream", func(t *testing.T) {
+		var captured *http.Request
+		httpmock.Activate()
+		httpmock.RegisterResponder(http.MethodGet, "https://www.fcuk.com/v1/ping", func(req *http.Request) (*http.Response, error) {
+			captured = req
+			return httpmock.NewStringResponse(http.StatusOK, `{"ping": "pong"}`), nil
+		})
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 2*time.Second)
+		defer cancel()
+
+		_, err := Get(ctx, "https://www.fcuk.com/v1/ping")
+		assert.NoError(t, err)
+
+		timeoutMs, err := strconv.Atoi(captured.Header.Get(RequestTimeoutHeaderKey))
+		assert.NoError(t, err)
+		assert.True(t, timeoutMs > 1000 && timeoutMs <= 2000)
+		assert.Equal(t, ctx, captured.Context())
+	})
+
+	t.Run("Happy - no header without deadline", func(t *testing.T) {
+		var captured *http.Request
+		httpmock.Activate()
+		httpmock.RegisterResponder(http.MethodGet, "https://www.fcuk.com/v1/ping", func(req *http.Request) (*http.Response, error) {
+			captured = req
+			return httpmock.NewStringResponse(http.StatusOK, `{"ping": "pong"}`), nil
+		})
+
+		_, err := Get(context.TODO(), "https://www.fcuk.com/v1/ping")
+		assert.NoError(t, err)
+		assert.Equal(t, "", captured.Header.Get(RequestTimeoutHeaderKey))
+	})
+
+	t.Run("Error - expired deadline is not sent", func(t *testing.T) {
+		calls := 0
+		httpmock.Activate()
+		httpmock.RegisterResponder(http.MethodGet, "https://www.fcuk.com/v1/ping", func(req *http.Request) (*http.Response, error) {
+			calls++
+			return httpmock.NewStringResponse(http.StatusOK, `{"ping": "pong"}`), nil
+		})
+
+		ctx, cancel := context.WithDeadline(context.TODO(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		_, err := Get(ctx, "https://www.fcuk.com/v1/ping")
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("Error - cancelled context stops the call", func(t *testing.T) {
+		httpmock.Activate()
+		httpmock.RegisterResponder(http.MethodGet, "https://www.fcuk.com/v1/slow", func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		})
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		_, err := NewClient().Get(ctx, "https://www.fcuk.com/v1/slow")
+		assert.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
@@ -1,10 +1,19 @@
 package ginney
 
 const (
-	GinContextKey          = "ad1ad1b903a4711506a2bfd6a8fd9086d2aaee36fc267b9be847963b9412b95e"
-	CorrelationIdHeaderKey = "X-Correlation-ID"
-	ContentTypeHeaderKey   = "Content-Type"
-	CensoredFieldText      = "[HIDDEN_FIELD]"
+	GinContextKey                  = "ad1ad1b903a4711506a2bfd6a8fd9086d2aaee36fc267b9be847963b9412b95e"
+	OperationNameKey               = "bed8739921295ffe20ab7d73db0098db95b0c10a54db747d4ee25626a541173f"
+	TraceContextKey                = "2da642d35357739b6825ba55534e6a31eb43c60871fc84df6ebfbe38e676c5f2"
+	CorrelationIdHeaderKey         = "X-Correlation-ID"
+	OriginalCorrelationIdHeaderKey = "X-Original-Correlation-ID"
+	TraceParentHeaderKey           = "traceparent"
+	TraceStateHeaderKey            = "tracestate"
+	ContentTypeHeaderKey           = "Content-Type"
+	AcceptHeaderKey                = "Accept"
+	RequestTimeoutHeaderKey        = "X-Request-Timeout"
+	ContentTypeJSON                = "application/json"
+	CensoredFieldText              = "[HIDDEN_FIELD]"
+	DefaultMaxBodyLogSize          = 64 * 1024
 )
 
 var (
@@ -15,4 +24,10 @@ var (
 		"file",
 		"phoneNumber",
 	}
+
+	// censoring by json path e.g. "customer.cards[*].pan", "[*]" matches any array index and "*" matches any key
+	RequestBodyPathCensoredList = []string{}
+
+	// values nested deeper than this are censored as a whole, zero or less disables the limit
+	CensoredMaxDepth = 32
 )
@@ -32,9 +32,28 @@ func FromContextToGrpcOutgoingContext(ctx context.Context) context.Context {
 		return ctx
 	}
 
+	ctx = withOutgoingTraceContext(ctx)
+
 	correlationId := ginContext.GetHeader(CorrelationIdHeaderKey)
 	if correlationId == "" {
 		return ctx
 	}
 	return metadata.AppendToOutgoingContext(ctx, CorrelationIdHeaderKey, correlationId)
 }
+
+// CorrelationIdFromContext looks in the outgoing metadata, the gin context and then the incoming metadata
+func CorrelationIdFromContext(ctx context.Context) string {
+	if meta, ok := metadata.FromOutgoingContext(ctx); ok {
+		if correlationIds := meta.Get(CorrelationIdHeaderKey); len(correlationIds) > 0 && correlationIds[0] != "" {
+			return correlationIds[0]
+		}
+	}
+
+	if ginContext, err := FromContextToGinContext(ctx); err == nil {
+		if correlationId := ginContext.GetHeader(CorrelationIdHeaderKey); correlationId != "" {
+			return correlationId
+		}
+	}
+
+	return correlationIdFromIncomingContext(ctx)
+}
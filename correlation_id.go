@@ -0,0 +1,165 @@
+package ginney
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/pkg/errors"
+	"math/big"
+	"regexp"
+	"time"
+)
+
+type CorrelationIdGenerator func() string
+
+type CorrelationIdValidator func(correlationId string) error
+
+type InvalidCorrelationIdPolicy int
+
+const (
+	ReplaceInvalidCorrelationId InvalidCorrelationIdPolicy = iota
+	RejectInvalidCorrelationId
+	KeepOriginalCorrelationId
+)
+
+type CorrelationIdOption func(config *correlationIdConfig)
+
+type correlationIdConfig struct {
+	generator     CorrelationIdGenerator
+	validators    []CorrelationIdValidator
+	invalidPolicy InvalidCorrelationIdPolicy
+}
+
+func newCorrelationIdConfig(opts []CorrelationIdOption) *correlationIdConfig {
+	config := &correlationIdConfig{
+		generator:     NewUUIDv4,
+		invalidPolicy: ReplaceInvalidCorrelationId,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+func (config *correlationIdConfig) validate(correlationId string) error {
+	for _, validator := range config.validators {
+		if err := validator(correlationId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func WithCorrelationIdGenerator(generator CorrelationIdGenerator) CorrelationIdOption {
+	return func(config *correlationIdConfig) {
+		if generator != nil {
+			config.generator = generator
+		}
+	}
+}
+
+func WithCorrelationIdValidators(validators ...CorrelationIdValidator) CorrelationIdOption {
+	return func(config *correlationIdConfig) {
+		config.validators = append(config.validators, validators...)
+	}
+}
+
+func WithInvalidCorrelationIdPolicy(policy InvalidCorrelationIdPolicy) CorrelationIdOption {
+	return func(config *correlationIdConfig) {
+		config.invalidPolicy = policy
+	}
+}
+
+func CorrelationIdMaxLengthValidator(maxLength int) CorrelationIdValidator {
+	return func(correlationId string) error {
+		if len(correlationId) > maxLength {
+			return errors.Errorf("%s must not be longer than %d characters", CorrelationIdHeaderKey, maxLength)
+		}
+		return nil
+	}
+}
+
+func CorrelationIdPatternValidator(pattern *regexp.Regexp) CorrelationIdValidator {
+	return func(correlationId string) error {
+		if !pattern.MatchString(correlationId) {
+			return errors.Errorf("%s must match %s", CorrelationIdHeaderKey, pattern.String())
+		}
+		return nil
+	}
+}
+
+func NewUUIDv4() string {
+	id := randomBytes(16)
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+	return formatUUID(id)
+}
+
+func NewUUIDv7() string {
+	id := randomBytes(16)
+	putUnixMilli(id, time.Now())
+	id[6] = (id[6] & 0x0f) | 0x70
+	id[8] = (id[8] & 0x3f) | 0x80
+	return formatUUID(id)
+}
+
+func NewULID() string {
+	const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	id := randomBytes(16)
+	putUnixMilli(id, time.Now())
+
+	// 128 bits are encoded as 26 characters of 5 bits, the first character holds the 3 highest bits
+	value := new(big.Int).SetBytes(id)
+	mask := big.NewInt(31)
+	encoded := make([]byte, 26)
+	for index := len(encoded) - 1; index >= 0; index-- {
+		encoded[index] = crockfordAlphabet[new(big.Int).And(value, mask).Int64()]
+		value.Rsh(value, 5)
+	}
+	return string(encoded)
+}
+
+func NewKSUID() string {
+	const (
+		base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+		ksuidEpoch     = 1400000000
+	)
+
+	id := randomBytes(20)
+	timestamp := uint32(time.Now().Unix() - ksuidEpoch)
+	id[0] = byte(timestamp >> 24)
+	id[1] = byte(timestamp >> 16)
+	id[2] = byte(timestamp >> 8)
+	id[3] = byte(timestamp)
+
+	value := new(big.Int).SetBytes(id)
+	base := big.NewInt(62)
+	remainder := new(big.Int)
+	encoded := make([]byte, 27)
+	for index := len(encoded) - 1; index >= 0; index-- {
+		value.QuoRem(value, base, remainder)
+		encoded[index] = base62Alphabet[remainder.Int64()]
+	}
+	return string(encoded)
+}
+
+func randomBytes(length int) []byte {
+	data := make([]byte, length)
+	if _, err := rand.Read(data); err != nil {
+		panic(fmt.Sprintf("ginney: could not read random bytes: %v", err))
+	}
+	return data
+}
+
+func putUnixMilli(id []byte, now time.Time) {
+	milliseconds := uint64(now.UnixNano() / int64(time.Millisecond))
+	for index := 0; index < 6; index++ {
+		id[index] = byte(milliseconds >> uint(8*(5-index)))
+	}
+}
+
+func formatUUID(id []byte) string {
+	encoded := hex.EncodeToString(id)
+	return encoded[0:8] + "-" + encoded[8:12] + "-" + encoded[12:16] + "-" + encoded[16:20] + "-" + encoded[20:32]
+}
@@ -0,0 +1,47 @@
+package ginney
+
+import (
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestCorrelationIdGenerators(t *testing.T) {
+	t.Run("Happy - UUIDv4", func(t *testing.T) {
+		id := NewUUIDv4()
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+		assert.NotEqual(t, id, NewUUIDv4())
+	})
+
+	t.Run("Happy - UUIDv7 is time ordered", func(t *testing.T) {
+		id := NewUUIDv7()
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+		assert.True(t, id[:13] <= NewUUIDv7()[:13])
+	})
+
+	t.Run("Happy - ULID", func(t *testing.T) {
+		id := NewULID()
+		assert.Regexp(t, `^[0-7][0-9A-HJKMNP-TV-Z]{25}$`, id)
+		assert.NotEqual(t, id, NewULID())
+	})
+
+	t.Run("Happy - KSUID", func(t *testing.T) {
+		id := NewKSUID()
+		assert.Regexp(t, `^[0-9A-Za-z]{27}$`, id)
+		assert.NotEqual(t, id, NewKSUID())
+	})
+}
+
+func TestCorrelationIdValidators(t *testing.T) {
+	t.Run("Happy - max length", func(t *testing.T) {
+		validator := CorrelationIdMaxLengthValidator(5)
+		assert.NoError(t, validator("12345"))
+		assert.Error(t, validator("123456"))
+	})
+
+	t.Run("Happy - pattern", func(t *testing.T) {
+		validator := CorrelationIdPatternValidator(regexp.MustCompile(`^[a-z-]+$`))
+		assert.NoError(t, validator("random-uuid"))
+		assert.Error(t, validator("random uuid"))
+	})
+}
@@ -0,0 +1,86 @@
+package ginney
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"mime"
+	"net/http"
+)
+
+type decodedEnvelope struct {
+	Status       string          `json:"status"`
+	Code         string          `json:"code"`
+	Message      string          `json:"message"`
+	HumanMessage *string         `json:"humanMessage"`
+	Data         json.RawMessage `json:"data"`
+	Errors       []EnvelopeError `json:"errors"`
+	Details      []interface{}   `json:"details"`
+}
+
+// DecodeEnvelope reads and closes the body, a fail envelope is returned as *Error with the upstream status
+// and data of a success envelope is unmarshalled into out when out is not nil, an empty body such as 204 is a success
+func DecodeEnvelope(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "fail to read response body")
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return NewError(resp.StatusCode, http.StatusText(resp.StatusCode))
+		}
+		return nil
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get(ContentTypeHeaderKey)); mediaType == ProblemJSONContentType {
+		return decodeProblem(resp.StatusCode, body)
+	}
+
+	var envelope decodedEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return NewError(resp.StatusCode, http.StatusText(resp.StatusCode)).WithCause(err)
+		}
+		return errors.Wrap(err, "fail to decode response envelope")
+	}
+
+	if envelope.Status == StatusFail || resp.StatusCode >= http.StatusBadRequest {
+		upstreamErr := NewError(resp.StatusCode, envelope.Message).WithCode(envelope.Code)
+		// error bodies of proxies and other frameworks are JSON without the envelope fields
+		if upstreamErr.Message == "" {
+			upstreamErr.Message = http.StatusText(resp.StatusCode)
+		}
+		upstreamErr.HumanMessage = envelope.HumanMessage
+		upstreamErr.Errors = envelope.Errors
+		upstreamErr.Details = envelope.Details
+		return upstreamErr
+	}
+
+	if out == nil || len(envelope.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return errors.Wrap(err, "fail to decode response data")
+	}
+	return nil
+}
+
+func decodeProblem(statusCode int, body []byte) error {
+	var problem ProblemDetails
+	if err := json.Unmarshal(body, &problem); err != nil {
+		return NewError(statusCode, http.StatusText(statusCode)).WithCause(err)
+	}
+
+	upstreamErr := NewError(statusCode, problem.Detail).WithCode(problem.Code)
+	if upstreamErr.Message == "" {
+		upstreamErr.Message = problem.Title
+	}
+	upstreamErr.HumanMessage = problem.HumanMessage
+	upstreamErr.Errors = problem.Errors
+	upstreamErr.Details = problem.Details
+	return upstreamErr
+}
@@ -0,0 +1,160 @@
+package ginney
+
+import (
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type fakeReadCloser struct {
+	*strings.Reader
+	closed bool
+}
+
+func (r *fakeReadCloser) Close() error {
+	r.closed = true
+	return nil
+}
+
+func newEnvelopeResponse(statusCode int, contentType, body string) (*http.Response, *fakeReadCloser) {
+	readCloser := &fakeReadCloser{Reader: strings.NewReader(body)}
+	resp := &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{},
+		Body:       readCloser,
+	}
+	resp.Header.Set(ContentTypeHeaderKey, contentType)
+	return resp, readCloser
+}
+
+func TestDecodeEnvelope(t *testing.T) {
+	type account struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	t.Run("Happy - data is unmarshalled and body is closed", func(t *testing.T) {
+		resp, body := newEnvelopeResponse(http.StatusOK, "application/json", `{"status":"success","message":"OK","data":{"id":"1","name":"a"}}`)
+
+		var out account
+		err := DecodeEnvelope(resp, &out)
+
+		assert.NoError(t, err)
+		assert.Equal(t, account{Id: "1", Name: "a"}, out)
+		assert.True(t, body.closed)
+	})
+
+	t.Run("Happy - nil target ignores data", func(t *testing.T) {
+		resp, _ := newEnvelopeResponse(http.StatusOK, "application/json", `{"status":"success","message":"OK","data":{"id":"1"}}`)
+
+		assert.NoError(t, DecodeEnvelope(resp, nil))
+	})
+
+	t.Run("Happy - empty body of a success status", func(t *testing.T) {
+		resp, body := newEnvelopeResponse(http.StatusNoContent, "", ``)
+
+		var out account
+		err := DecodeEnvelope(resp, &out)
+
+		assert.NoError(t, err)
+		assert.Equal(t, account{}, out)
+		assert.True(t, body.closed)
+	})
+
+	t.Run("Error - fail envelope becomes *Error with upstream status", func(t *testing.T) {
+		resp, _ := newEnvelopeResponse(http.StatusNotFound, "application/json",
+			`{"status":"fail","code":"ACC-404","message":"account not found","humanMessage":"ไม่พบบัญชี","errors":[{"field":"id","message":"id is unknown"}]}`)
+
+		var out account
+		err := DecodeEnvelope(resp, &out)
+
+		ginneyErr, ok := err.(*Error)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, ginneyErr.HttpStatus)
+		assert.Equal(t, codes.NotFound, ginneyErr.GrpcCode)
+		assert.Equal(t, "ACC-404", ginneyErr.Code)
+		assert.Equal(t, "account not found", ginneyErr.Message)
+		assert.Equal(t, "ไม่พบบัญชี", *ginneyErr.HumanMessage)
+		assert.Equal(t, []EnvelopeError{{Field: "id", Message: "id is unknown"}}, ginneyErr.Errors)
+		assert.Equal(t, account{}, out)
+	})
+
+	t.Run("Error - fail status with 200", func(t *testing.T) {
+		resp, _ := newEnvelopeResponse(http.StatusOK, "application/json", `{"status":"fail","message":"rejected"}`)
+
+		err := DecodeEnvelope(resp, nil)
+
+		assert.Equal(t, http.StatusOK, AsError(err).HttpStatus)
+		assert.Equal(t, "rejected", AsError(err).Message)
+	})
+
+	t.Run("Error - problem json", func(t *testing.T) {
+		resp, _ := newEnvelopeResponse(http.StatusConflict, ProblemJSONContentType,
+			`{"type":"about:blank","title":"Conflict","status":409,"detail":"insufficient balance","code":"BAL-409"}`)
+
+		err := DecodeEnvelope(resp, nil)
+
+		assert.Equal(t, http.StatusConflict, AsError(err).HttpStatus)
+		assert.Equal(t, "BAL-409", AsError(err).Code)
+		assert.Equal(t, "insufficient balance", AsError(err).Message)
+	})
+
+	t.Run("Error - non envelope error body keeps upstream status", func(t *testing.T) {
+		resp, _ := newEnvelopeResponse(http.StatusBadGateway, "text/html", `<html>bad gateway</html>`)
+
+		err := DecodeEnvelope(resp, nil)
+
+		assert.Equal(t, http.StatusBadGateway, AsError(err).HttpStatus)
+		assert.Equal(t, "Bad Gateway", AsError(err).Message)
+	})
+
+	t.Run("Error - empty body of an error status", func(t *testing.T) {
+		resp, _ := newEnvelopeResponse(http.StatusServiceUnavailable, "", ``)
+
+		err := DecodeEnvelope(resp, nil)
+
+		assert.Equal(t, http.StatusServiceUnavailable, AsError(err).HttpStatus)
+		assert.Equal(t, "Service Unavailable", AsError(err).Message)
+	})
+
+	t.Run("Error - JSON error body without envelope fields", func(t *testing.T) {
+		resp, _ := newEnvelopeResponse(http.StatusBadGateway, "application/json", `{"error":"x"}`)
+
+		err := DecodeEnvelope(resp, nil)
+
+		assert.Equal(t, http.StatusBadGateway, AsError(err).HttpStatus)
+		assert.Equal(t, "Bad Gateway", AsError(err).Message)
+	})
+
+	t.Run("Error - non envelope success body", func(t *testing.T) {
+		resp, _ := newEnvelopeResponse(http.StatusOK, "text/plain", `pong`)
+
+		err := DecodeEnvelope(resp, nil)
+
+		assert.Error(t, err)
+		_, ok := err.(*Error)
+		assert.False(t, ok)
+	})
+
+	t.Run("Error - data does not match target", func(t *testing.T) {
+		resp, _ := newEnvelopeResponse(http.StatusOK, "application/json", `{"status":"success","message":"OK","data":[1]}`)
+
+		var out account
+		assert.Error(t, DecodeEnvelope(resp, &out))
+	})
+
+	t.Run("Error - body cannot be read", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: ioutil.NopCloser(errorReader{})}
+
+		assert.Error(t, DecodeEnvelope(resp, nil))
+	})
+}
+
+type errorReader struct{}
+
+func (errorReader) Read(p []byte) (int, error) {
+	return 0, http.ErrBodyReadAfterClose
+}
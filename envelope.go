@@ -0,0 +1,191 @@
+package ginney
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+// Envelope is the single response shape of ginney, optional fields are rendered only when they are set
+type Envelope struct {
+	Status       string
+	Code         string
+	Message      string
+	HumanMessage *string
+	Data         interface{}
+	Meta         *Meta
+	Errors       []EnvelopeError
+	Details      []interface{}
+
+	// code, humanMessage and data are rendered even when empty once they are set through the options
+	hasCode         bool
+	hasHumanMessage bool
+	hasData         bool
+}
+
+type Meta struct {
+	Pagination    *Pagination `json:"pagination,omitempty"`
+	RequestId     string      `json:"requestId,omitempty"`
+	CorrelationId string      `json:"correlationId,omitempty"`
+	DurationMs    *float64    `json:"durationMs,omitempty"`
+}
+
+type Pagination struct {
+	Limit      int              `json:"limit"`
+	Offset     *int             `json:"offset,omitempty"`
+	Page       *int             `json:"page,omitempty"`
+	Total      *int64           `json:"total,omitempty"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+	PrevCursor string           `json:"prevCursor,omitempty"`
+	Links      *PaginationLinks `json:"links,omitempty"`
+}
+
+type PaginationLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+type EnvelopeError struct {
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Rule    string `json:"rule,omitempty"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+type EnvelopeOption func(envelope *Envelope)
+
+func NewEnvelope(status, message string, opts ...EnvelopeOption) *Envelope {
+	envelope := &Envelope{
+		Status:  status,
+		Message: message,
+	}
+	for _, opt := range opts {
+		opt(envelope)
+	}
+	return envelope
+}
+
+func WithEnvelopeCode(code string) EnvelopeOption {
+	return func(envelope *Envelope) {
+		envelope.Code = code
+		envelope.hasCode = true
+	}
+}
+
+// WithEnvelopeHumanMessage renders humanMessage even when it is nil, as null
+func WithEnvelopeHumanMessage(humanMessage *string) EnvelopeOption {
+	return func(envelope *Envelope) {
+		envelope.HumanMessage = humanMessage
+		envelope.hasHumanMessage = true
+	}
+}
+
+func WithEnvelopeData(data interface{}) EnvelopeOption {
+	return func(envelope *Envelope) {
+		envelope.Data = data
+		envelope.hasData = true
+	}
+}
+
+func WithEnvelopeErrors(errors ...EnvelopeError) EnvelopeOption {
+	return func(envelope *Envelope) {
+		envelope.Errors = append(envelope.Errors, errors...)
+	}
+}
+
+func WithEnvelopeDetails(details ...interface{}) EnvelopeOption {
+	return func(envelope *Envelope) {
+		envelope.Details = append(envelope.Details, details...)
+	}
+}
+
+func WithEnvelopePagination(pagination Pagination) EnvelopeOption {
+	return func(envelope *Envelope) {
+		envelope.meta().Pagination = &pagination
+	}
+}
+
+func WithEnvelopeRequestId(requestId string) EnvelopeOption {
+	return func(envelope *Envelope) {
+		envelope.meta().RequestId = requestId
+	}
+}
+
+func WithEnvelopeCorrelationId(correlationId string) EnvelopeOption {
+	return func(envelope *Envelope) {
+		envelope.meta().CorrelationId = correlationId
+	}
+}
+
+func WithEnvelopeDuration(duration time.Duration) EnvelopeOption {
+	return func(envelope *Envelope) {
+		durationMs := float64(duration) / float64(time.Millisecond)
+		envelope.meta().DurationMs = &durationMs
+	}
+}
+
+func (e *Envelope) meta() *Meta {
+	if e.Meta == nil {
+		e.Meta = &Meta{}
+	}
+	return e.Meta
+}
+
+// MarshalJSON keeps the key order of the former response structs, so existing clients see the same bytes
+func (e Envelope) MarshalJSON() ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	buffer.WriteByte('{')
+
+	fields := 0
+	writeField := func(key string, value interface{}) error {
+		valueBytes, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if fields > 0 {
+			buffer.WriteByte(',')
+		}
+		keyBytes, _ := json.Marshal(key)
+		buffer.Write(keyBytes)
+		buffer.WriteByte(':')
+		buffer.Write(valueBytes)
+		fields++
+		return nil
+	}
+
+	type envelopeField struct {
+		key     string
+		value   interface{}
+		present bool
+	}
+
+	for _, field := range []envelopeField{
+		{key: "status", value: e.Status, present: true},
+		{key: "code", value: e.Code, present: e.hasCode || e.Code != ""},
+		{key: "message", value: e.Message, present: true},
+		{key: "humanMessage", value: e.HumanMessage, present: e.hasHumanMessage || e.HumanMessage != nil},
+		{key: "data", value: e.Data, present: e.hasData || e.Data != nil},
+		{key: "meta", value: e.Meta, present: e.Meta != nil},
+		{key: "errors", value: e.Errors, present: len(e.Errors) > 0},
+		{key: "details", value: e.Details, present: len(e.Details) > 0},
+	} {
+		if !field.present {
+			continue
+		}
+		if err := writeField(field.key, field.value); err != nil {
+			return nil, err
+		}
+	}
+
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+func JSONEnvelope(c *gin.Context, httpStatus int, envelope *Envelope) {
+	c.AbortWithStatusJSON(httpStatus, envelope)
+}
@@ -0,0 +1,157 @@
+package ginney
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// the response structs used before the envelope, kept to prove the wire format is unchanged
+type legacySuccessHumanResponse struct {
+	Status       string      `json:"status"`
+	Message      string      `json:"message"`
+	HumanMessage *string     `json:"humanMessage"`
+	Data         interface{} `json:"data"`
+}
+
+type legacyErrorHumanResponse struct {
+	Status       string  `json:"status"`
+	Message      string  `json:"message"`
+	HumanMessage *string `json:"humanMessage"`
+}
+
+type legacySuccessCodeResponse struct {
+	Status  string      `json:"status"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+}
+
+type legacyErrorCodeResponse struct {
+	Status  string `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func renderResponse(render func(c *gin.Context)) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	render(c)
+	return w
+}
+
+func TestJSONResponse_ByteIdentical(t *testing.T) {
+	humanMessage := "บัญชี <ของคุณ>"
+	data := map[string]interface{}{"id": 1, "name": "a&b"}
+
+	cases := []struct {
+		name     string
+		render   func(c *gin.Context)
+		expected interface{}
+	}{
+		{
+			name:     "JSONSuccessResponse",
+			render:   func(c *gin.Context) { JSONSuccessResponse(c, data) },
+			expected: BaseSuccessResponse{Status: StatusSuccess, Message: MessageOk, Data: data},
+		},
+		{
+			name:     "JSONSuccessResponse nil data",
+			render:   func(c *gin.Context) { JSONSuccessResponse(c, nil) },
+			expected: BaseSuccessResponse{Status: StatusSuccess, Message: MessageOk},
+		},
+		{
+			name:     "JSONSuccessHumanResponse",
+			render:   func(c *gin.Context) { JSONSuccessHumanResponse(c, data, &humanMessage) },
+			expected: legacySuccessHumanResponse{Status: StatusSuccess, Message: MessageOk, HumanMessage: &humanMessage, Data: data},
+		},
+		{
+			name:     "JSONSuccessHumanResponse nil human message",
+			render:   func(c *gin.Context) { JSONSuccessHumanResponse(c, data, nil) },
+			expected: legacySuccessHumanResponse{Status: StatusSuccess, Message: MessageOk, Data: data},
+		},
+		{
+			name: "JSONErrorHumanResponse",
+			render: func(c *gin.Context) {
+				JSONErrorHumanResponse(c, http.StatusBadRequest, fmt.Errorf("bad"), &humanMessage)
+			},
+			expected: legacyErrorHumanResponse{Status: StatusFail, Message: "bad", HumanMessage: &humanMessage},
+		},
+		{
+			name:     "JSONErrorHumanResponse nil human message",
+			render:   func(c *gin.Context) { JSONErrorHumanResponse(c, http.StatusBadRequest, fmt.Errorf("bad"), nil) },
+			expected: legacyErrorHumanResponse{Status: StatusFail, Message: "bad"},
+		},
+		{
+			name:     "JSONSuccessCodeResponse",
+			render:   func(c *gin.Context) { JSONSuccessCodeResponse(c, data, "200") },
+			expected: legacySuccessCodeResponse{Status: StatusSuccess, Code: "200", Message: MessageOk, Data: data},
+		},
+		{
+			name:     "JSONErrorCodeResponse empty code",
+			render:   func(c *gin.Context) { JSONErrorCodeResponse(c, http.StatusBadRequest, fmt.Errorf("bad"), "") },
+			expected: legacyErrorCodeResponse{Status: StatusFail, Message: "bad"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			expected, err := json.Marshal(tc.expected)
+			assert.NoError(t, err)
+
+			w := renderResponse(tc.render)
+			assert.Equal(t, string(expected), w.Body.String())
+		})
+	}
+}
+
+func TestNewEnvelope(t *testing.T) {
+	t.Run("Happy - only status and message by default", func(t *testing.T) {
+		jsonBytes, err := json.Marshal(NewEnvelope(StatusFail, "bad"))
+		assert.NoError(t, err)
+		assert.Equal(t, `{"status":"fail","message":"bad"}`, string(jsonBytes))
+	})
+
+	t.Run("Happy - all fields in order", func(t *testing.T) {
+		offset := 20
+		total := int64(42)
+		humanMessage := "ลองใหม่"
+
+		envelope := NewEnvelope(StatusFail, "invalid request",
+			WithEnvelopeErrors(EnvelopeError{Field: "name", Code: "required", Message: "name is required"}),
+			WithEnvelopeData([]int{1}),
+			WithEnvelopeHumanMessage(&humanMessage),
+			WithEnvelopeCode("REQ-400"),
+			WithEnvelopePagination(Pagination{Limit: 10, Offset: &offset, Total: &total}),
+			WithEnvelopeRequestId("req-1"),
+			WithEnvelopeCorrelationId("random-uuid"),
+			WithEnvelopeDuration(1500*time.Microsecond),
+			WithEnvelopeDetails("detail"),
+		)
+
+		jsonBytes, err := json.Marshal(envelope)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"status":"fail","code":"REQ-400","message":"invalid request","humanMessage":"ลองใหม่","data":[1],`+
+			`"meta":{"pagination":{"limit":10,"offset":20,"total":42},"requestId":"req-1","correlationId":"random-uuid","durationMs":1.5},`+
+			`"errors":[{"field":"name","code":"required","message":"name is required"}],"details":["detail"]}`, string(jsonBytes))
+	})
+
+	t.Run("Error - data cannot be marshalled", func(t *testing.T) {
+		_, err := json.Marshal(NewEnvelope(StatusSuccess, MessageOk, WithEnvelopeData(make(chan int))))
+		assert.Error(t, err)
+	})
+}
+
+func TestJSONEnvelope(t *testing.T) {
+	w := renderResponse(func(c *gin.Context) {
+		JSONEnvelope(c, http.StatusCreated, NewEnvelope(StatusSuccess, MessageOk, WithEnvelopeData("created")))
+	})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"status":"success","message":"OK","data":"created"}`, w.Body.String())
+}
@@ -0,0 +1,259 @@
+package ginney
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+type Error struct {
+	HttpStatus   int
+	GrpcCode     codes.Code
+	Code         string
+	Message      string
+	HumanMessage *string
+	Details      []interface{}
+	Errors       []EnvelopeError
+	Cause        error
+}
+
+func NewError(httpStatus int, message string) *Error {
+	return &Error{
+		HttpStatus: httpStatus,
+		GrpcCode:   HttpStatusToGrpcCode(httpStatus),
+		Message:    message,
+	}
+}
+
+func (e *Error) Error() string {
+	if e.Cause == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Cause.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+func (e *Error) WithGrpcCode(grpcCode codes.Code) *Error {
+	clone := *e
+	clone.GrpcCode = grpcCode
+	return &clone
+}
+
+func (e *Error) WithCode(code string) *Error {
+	clone := *e
+	clone.Code = code
+	return &clone
+}
+
+func (e *Error) WithHumanMessage(humanMessage string) *Error {
+	clone := *e
+	clone.HumanMessage = &humanMessage
+	return &clone
+}
+
+func (e *Error) WithDetails(details ...interface{}) *Error {
+	clone := *e
+	clone.Details = append(append([]interface{}{}, e.Details...), details...)
+	return &clone
+}
+
+func (e *Error) WithErrors(errors ...EnvelopeError) *Error {
+	clone := *e
+	clone.Errors = append(append([]EnvelopeError{}, e.Errors...), errors...)
+	return &clone
+}
+
+func (e *Error) WithCause(cause error) *Error {
+	clone := *e
+	clone.Cause = cause
+	return &clone
+}
+
+// withDefaults fills the statuses of an Error written as a literal, gin ignores the status 0 and codes.OK is no error
+func (e *Error) withDefaults() *Error {
+	if e.HttpStatus != 0 && e.GrpcCode != codes.OK {
+		return e
+	}
+
+	clone := *e
+	if clone.HttpStatus == 0 {
+		clone.HttpStatus = http.StatusInternalServerError
+	}
+	if clone.GrpcCode == codes.OK {
+		clone.GrpcCode = HttpStatusToGrpcCode(clone.HttpStatus)
+	}
+	return &clone
+}
+
+func (e *Error) GRPCStatus() *status.Status {
+	e = e.withDefaults()
+	st := status.New(e.GrpcCode, e.Message)
+
+	var details []proto.Message
+	if e.Code != "" {
+		details = append(details, &errdetails.ErrorInfo{Reason: e.Code})
+	}
+	if e.HumanMessage != nil {
+		details = append(details, &errdetails.LocalizedMessage{Message: *e.HumanMessage})
+	}
+	if len(e.Errors) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, fieldError := range e.Errors {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       fieldError.Field,
+				Description: fieldError.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+	for _, detail := range e.Details {
+		if protoDetail, ok := detail.(proto.Message); ok {
+			details = append(details, protoDetail)
+		}
+	}
+	if len(details) == 0 {
+		return st
+	}
+
+	stWithDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return stWithDetails
+}
+
+// AsError finds the ginney error in the chain of err, any other error and nil become an internal server error
+// with a generic message so that internal details are not sent to clients, the original error is kept as the cause
+func AsError(err error) *Error {
+	var ginneyErr *Error
+	if errors.As(err, &ginneyErr) {
+		return ginneyErr
+	}
+
+	internalErr := NewError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	if err == nil {
+		return internalErr
+	}
+	return internalErr.WithCause(err)
+}
+
+func JSONError(c *gin.Context, err error) {
+	if shouldRenderProblem(c) {
+		JSONProblem(c, err)
+		return
+	}
+
+	ginneyErr := AsError(err).withDefaults()
+
+	// code, humanMessage, errors and details are left out of the fail envelope when they are not set
+	opts := make([]EnvelopeOption, 0, 4)
+	if ginneyErr.Code != "" {
+		opts = append(opts, WithEnvelopeCode(ginneyErr.Code))
+	}
+	if ginneyErr.HumanMessage != nil {
+		opts = append(opts, WithEnvelopeHumanMessage(ginneyErr.HumanMessage))
+	}
+	if len(ginneyErr.Errors) > 0 {
+		opts = append(opts, WithEnvelopeErrors(ginneyErr.Errors...))
+	}
+	if len(ginneyErr.Details) > 0 {
+		opts = append(opts, WithEnvelopeDetails(ginneyErr.Details...))
+	}
+
+	JSONEnvelope(c, ginneyErr.HttpStatus, NewEnvelope(StatusFail, ginneyErr.Message, opts...))
+}
+
+func ErrorUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		res, err := handler(ctx, req)
+		return res, toGrpcError(err)
+	}
+}
+
+func ErrorStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return toGrpcError(handler(srv, ss))
+	}
+}
+
+func toGrpcError(err error) error {
+	var ginneyErr *Error
+	if err == nil || !errors.As(err, &ginneyErr) {
+		return err
+	}
+	return ginneyErr.GRPCStatus().Err()
+}
+
+func HttpStatusToGrpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
+		return codes.OK
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499:
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout, http.StatusRequestTimeout:
+		return codes.DeadlineExceeded
+	default:
+		if httpStatus >= 400 && httpStatus < 500 {
+			return codes.FailedPrecondition
+		}
+		return codes.Internal
+	}
+}
+
+func GrpcCodeToHttpStatus(grpcCode codes.Code) int {
+	switch grpcCode {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,147 @@
+package ginney
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var errFakeAccountNotFound = NewError(http.StatusNotFound, "account not found").WithCode("ACC-404")
+
+func TestError(t *testing.T) {
+	t.Run("Happy - builder doesn't mutate the original error", func(t *testing.T) {
+		cause := errors.New("sql: no rows in result set")
+		err := errFakeAccountNotFound.WithHumanMessage("ไม่พบบัญชี").WithCause(cause)
+
+		assert.Nil(t, errFakeAccountNotFound.HumanMessage)
+		assert.Nil(t, errFakeAccountNotFound.Cause)
+		assert.Equal(t, codes.NotFound, err.GrpcCode)
+		assert.Equal(t, "account not found: sql: no rows in result set", err.Error())
+		assert.True(t, errors.Is(err, cause))
+	})
+
+	t.Run("Happy - wrapped error is found by AsError", func(t *testing.T) {
+		err := errors.Wrap(errFakeAccountNotFound, "fail to get account")
+
+		assert.Equal(t, errFakeAccountNotFound, AsError(err))
+	})
+
+	t.Run("Happy - unknown error becomes internal server error", func(t *testing.T) {
+		cause := errors.New("boom")
+		err := AsError(cause)
+
+		assert.Equal(t, http.StatusInternalServerError, err.HttpStatus)
+		assert.Equal(t, codes.Internal, err.GrpcCode)
+		assert.Equal(t, "Internal Server Error", err.Message)
+		assert.Equal(t, cause, err.Cause)
+	})
+
+	t.Run("Happy - nil becomes internal server error", func(t *testing.T) {
+		err := AsError(nil)
+
+		assert.Equal(t, http.StatusInternalServerError, err.HttpStatus)
+		assert.Equal(t, "Internal Server Error", err.Message)
+		assert.Nil(t, err.Cause)
+	})
+
+	t.Run("Happy - grpc status carries code and human message as details", func(t *testing.T) {
+		st := errFakeAccountNotFound.WithHumanMessage("ไม่พบบัญชี").GRPCStatus()
+
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Equal(t, "account not found", st.Message())
+		assert.Len(t, st.Details(), 2)
+		assert.Equal(t, "ACC-404", st.Details()[0].(*errdetails.ErrorInfo).Reason)
+		assert.Equal(t, "ไม่พบบัญชี", st.Details()[1].(*errdetails.LocalizedMessage).Message)
+	})
+}
+
+func TestJSONError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Happy - ginney error", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(resp)
+
+		JSONError(c, errors.Wrap(errFakeAccountNotFound.WithHumanMessage("not found").WithDetails("id=1"), "fail"))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		assert.JSONEq(t, `{"status":"fail","code":"ACC-404","message":"account not found","humanMessage":"not found","details":["id=1"]}`, resp.Body.String())
+	})
+
+	t.Run("Happy - plain error", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(resp)
+
+		JSONError(c, errors.New("boom"))
+
+		var respBody map[string]interface{}
+		_ = json.Unmarshal(resp.Body.Bytes(), &respBody)
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		assert.Equal(t, map[string]interface{}{"status": StatusFail, "message": "Internal Server Error"}, respBody)
+	})
+
+	t.Run("Happy - error literal without statuses", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(resp)
+
+		JSONError(c, &Error{Message: "boom"})
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		assert.Equal(t, `{"status":"fail","message":"boom"}`, resp.Body.String())
+		assert.Equal(t, codes.Internal, (&Error{Message: "boom"}).GRPCStatus().Code())
+		assert.Equal(t, codes.NotFound, (&Error{HttpStatus: http.StatusNotFound, Message: "boom"}).GRPCStatus().Code())
+	})
+
+	t.Run("Happy - nil error", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(resp)
+
+		JSONError(c, nil)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		assert.Equal(t, `{"status":"fail","message":"Internal Server Error"}`, resp.Body.String())
+	})
+}
+
+func TestErrorUnaryServerInterceptor(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		interceptor := ErrorUnaryServerInterceptor()
+		_, err := interceptor(context.TODO(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, errors.Wrap(errFakeAccountNotFound, "fail")
+		})
+
+		st, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Equal(t, "account not found", st.Message())
+	})
+
+	t.Run("Happy - other errors are untouched", func(t *testing.T) {
+		interceptor := ErrorUnaryServerInterceptor()
+		_, err := interceptor(context.TODO(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.Aborted, "aborted")
+		})
+
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+}
+
+func TestErrorStreamServerInterceptor(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		interceptor := ErrorStreamServerInterceptor()
+		err := interceptor(nil, newFakeServerStream(context.TODO()), &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+			return NewError(http.StatusServiceUnavailable, "down")
+		})
+
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
@@ -3,7 +3,6 @@ package ginney
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"strings"
 	"time"
 )
@@ -20,26 +19,13 @@ func formatLog(eventTime time.Time, correlationId string, statusCode string, lat
 	)
 }
 
-func httpRequestBodyToString(body io.ReadCloser) string {
-	if body == nil {
-		return ""
-	}
-
-	var bodyData map[string]interface{}
-	err := json.NewDecoder(body).Decode(&bodyData)
-	if err != nil {
-		return fmt.Sprintf("%s", body)
-	}
-	return jsonBodyToString(bodyData)
-}
-
 func grpcRequestBodyToString(body interface{}) string {
 	jsonBytes, err := json.Marshal(body)
 	if err != nil {
 		return fmt.Sprintf("%s", body)
 	}
 
-	var bodyData map[string]interface{}
+	var bodyData interface{}
 	err = json.Unmarshal(jsonBytes, &bodyData)
 	if err != nil {
 		return fmt.Sprintf("%s", body)
@@ -48,23 +34,124 @@ func grpcRequestBodyToString(body interface{}) string {
 	return jsonBodyToString(bodyData)
 }
 
-func jsonBodyToString(jsonBody map[string]interface{}) string {
-	for key := range jsonBody {
-		if shouldKeyCensored(key) {
-			jsonBody[key] = CensoredFieldText
+func jsonBodyToString(jsonBody interface{}) string {
+	jsonBytes, _ := json.Marshal(censorJsonValue(jsonBody, nil, 0))
+	return string(jsonBytes)
+}
+
+func censorJsonValue(value interface{}, path []string, depth int) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		if isTooDeepToCensor(depth) {
+			return CensoredFieldText
+		}
+
+		for key, child := range typedValue {
+			childPath := appendJsonPath(path, key)
+			if shouldKeyCensored(key) || shouldPathCensored(childPath) {
+				typedValue[key] = CensoredFieldText
+				continue
+			}
+			typedValue[key] = censorJsonValue(child, childPath, depth+1)
 		}
+		return typedValue
+	case []interface{}:
+		if isTooDeepToCensor(depth) {
+			return CensoredFieldText
+		}
+
+		for index, child := range typedValue {
+			childPath := appendJsonPath(path, fmt.Sprintf("[%d]", index))
+			if shouldPathCensored(childPath) {
+				typedValue[index] = CensoredFieldText
+				continue
+			}
+			typedValue[index] = censorJsonValue(child, childPath, depth+1)
+		}
+		return typedValue
+	default:
+		return value
 	}
+}
 
-	jsonBytes, _ := json.Marshal(jsonBody)
-	return string(jsonBytes)
+func isTooDeepToCensor(depth int) bool {
+	return CensoredMaxDepth > 0 && depth >= CensoredMaxDepth
+}
+
+func appendJsonPath(path []string, segment string) []string {
+	childPath := make([]string, len(path), len(path)+1)
+	copy(childPath, path)
+	return append(childPath, segment)
 }
 
 func shouldKeyCensored(key string) bool {
 	loweredKey := strings.ToLower(key)
 	for _, censoredKeyWord := range RequestBodyKeyCensoredList {
-		if strings.Contains(loweredKey, censoredKeyWord) {
+		if strings.Contains(loweredKey, strings.ToLower(censoredKeyWord)) {
+			return true
+		}
+	}
+	return false
+}
+
+func shouldPathCensored(path []string) bool {
+	for _, censoredPath := range RequestBodyPathCensoredList {
+		if jsonPathMatches(splitJsonPath(censoredPath), path) {
 			return true
 		}
 	}
 	return false
 }
+
+func splitJsonPath(path string) []string {
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		// splitting "cards[*]" into "cards" and "[*]"
+		for part != "" {
+			bracket := strings.Index(part, "[")
+			switch {
+			case bracket < 0:
+				segments = append(segments, part)
+				part = ""
+			case bracket > 0:
+				segments = append(segments, part[:bracket])
+				part = part[bracket:]
+			default:
+				end := strings.Index(part, "]")
+				if end < 0 {
+					segments = append(segments, part)
+					part = ""
+					continue
+				}
+				segments = append(segments, part[:end+1])
+				part = part[end+1:]
+			}
+		}
+	}
+	return segments
+}
+
+func jsonPathMatches(pattern []string, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+
+	for index, segment := range pattern {
+		isIndex := strings.HasPrefix(path[index], "[")
+		switch {
+		case segment == "[*]" && isIndex:
+		case segment == "*" && !isIndex:
+		case strings.EqualFold(segment, path[index]):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func grpcStreamSummaryToString(received int64, sent int64) string {
+	return jsonBodyToString(map[string]interface{}{
+		"received": received,
+		"sent":     sent,
+	})
+}
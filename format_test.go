@@ -0,0 +1,58 @@
+package ginney
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestJsonBodyToString(t *testing.T) {
+	decode := func(body string) interface{} {
+		var bodyData interface{}
+		_ = json.Unmarshal([]byte(body), &bodyData)
+		return bodyData
+	}
+
+	t.Run("Happy - nested objects and arrays are censored by keyword", func(t *testing.T) {
+		body := decode(`{"user":{"name":"john","password":"x"},"keys":[{"secretKey":"y","id":1}],"phoneNumber":"0812345678"}`)
+
+		assert.Equal(t,
+			`{"keys":[{"id":1,"secretKey":"[HIDDEN_FIELD]"}],"phoneNumber":"[HIDDEN_FIELD]","user":{"name":"john","password":"[HIDDEN_FIELD]"}}`,
+			jsonBodyToString(body),
+		)
+	})
+
+	t.Run("Happy - top level array", func(t *testing.T) {
+		body := decode(`[{"password":"x"},"plain"]`)
+
+		assert.Equal(t, `[{"password":"[HIDDEN_FIELD]"},"plain"]`, jsonBodyToString(body))
+	})
+
+	t.Run("Happy - censored by json path", func(t *testing.T) {
+		defer func(paths []string) { RequestBodyPathCensoredList = paths }(RequestBodyPathCensoredList)
+		RequestBodyPathCensoredList = []string{"customer.cards[*].pan", "customer.*.ssn", "tokens[0]"}
+
+		body := decode(`{"customer":{"cards":[{"pan":"4111","brand":"visa"},{"pan":"5500"}],"spouse":{"ssn":"1"},"ssn":"2"},"tokens":["a","b"],"pan":"kept"}`)
+
+		assert.Equal(t,
+			`{"customer":{"cards":[{"brand":"visa","pan":"[HIDDEN_FIELD]"},{"pan":"[HIDDEN_FIELD]"}],"spouse":{"ssn":"[HIDDEN_FIELD]"},"ssn":"2"},"pan":"kept","tokens":["[HIDDEN_FIELD]","b"]}`,
+			jsonBodyToString(body),
+		)
+	})
+
+	t.Run("Happy - values deeper than max depth are censored", func(t *testing.T) {
+		defer func(maxDepth int) { CensoredMaxDepth = maxDepth }(CensoredMaxDepth)
+		CensoredMaxDepth = 2
+
+		body := decode(`{"a":{"b":{"c":"d"},"e":"f"}}`)
+
+		assert.Equal(t, `{"a":{"b":"[HIDDEN_FIELD]","e":"f"}}`, jsonBodyToString(body))
+	})
+}
+
+func TestSplitJsonPath(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		assert.Equal(t, []string{"customer", "cards", "[*]", "pan"}, splitJsonPath("customer.cards[*].pan"))
+		assert.Equal(t, []string{"[0]", "[1]", "id"}, splitJsonPath("[0][1].id"))
+	})
+}
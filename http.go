@@ -1,48 +1,296 @@
 package ginney
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
-func send(ctx context.Context, method string, url string, contentType string, body io.Reader) (*http.Response, error) {
-	ginContext, _ := FromContextToGinContext(ctx)
+// send takes origin when the caller built the request, its length, GetBody, host, trailer and close are kept on every attempt
+func (c *Client) send(ctx context.Context, method string, url string, header http.Header, body io.Reader, origin *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	var endSpan func(res *http.Response, err error)
+	if c.tracer != nil {
+		ctx, endSpan = c.tracer.StartClientSpan(ctx, method, c.resolveUrl(url))
+	}
+
+	res, err := c.sendThroughCircuitBreaker(ctx, method, url, header, body, origin)
+
+	if endSpan != nil {
+		endSpan(res, err)
+	}
+	if c.metrics != nil {
+		c.metrics.observe(ctx, method, c.resolveUrl(url), res, err, time.Since(start))
+	}
+
+	return res, err
+}
+
+func (c *Client) sendThroughCircuitBreaker(ctx context.Context, method string, url string, header http.Header, body io.Reader, origin *http.Request) (*http.Response, error) {
+	if c.circuitBreaker == nil {
+		return c.sendWithRetry(ctx, method, url, header, body, origin)
+	}
 
-	client := &http.Client{}
-	req, err := http.NewRequest(method, url, body)
+	done, err := c.circuitBreaker.allow(hostFromUrl(c.resolveUrl(url)))
 	if err != nil {
 		return nil, err
 	}
 
-	// setting headers
-	if ginContext != nil {
-		req.Header.Set(CorrelationIdHeaderKey, ginContext.GetHeader(CorrelationIdHeaderKey))
+	res, err := c.sendWithRetry(ctx, method, url, header, body, origin)
+	done(isHttpCircuitFailure(res, err))
+	return res, err
+}
+
+func (c *Client) sendWithRetry(ctx context.Context, method string, url string, header http.Header, body io.Reader, origin *http.Request) (*http.Response, error) {
+	ginContext, _ := FromContextToGinContext(ctx)
+
+	retryable := c.retryPolicy.allowsMethod(method)
+
+	// buffering body, so it can be sent again on retry
+	var bodyBytes []byte
+	if retryable && body != nil {
+		var err error
+		bodyBytes, err = ioutil.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
 	}
-	if contentType != "" {
-		req.Header.Set(ContentTypeHeaderKey, contentType)
+
+	for attempt := 0; ; attempt++ {
+		if retryable && body != nil {
+			body = bytes.NewReader(bodyBytes)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, c.resolveUrl(url), body)
+		if err != nil {
+			return nil, err
+		}
+		if origin != nil {
+			// a body buffered for retry already has its length, otherwise it is the body of origin as is
+			if req.GetBody == nil {
+				req.ContentLength = origin.ContentLength
+				req.GetBody = origin.GetBody
+			}
+			req.Host = origin.Host
+			req.Trailer = origin.Trailer
+			req.Close = origin.Close
+		}
+
+		// setting headers
+		for key, values := range header {
+			req.Header[key] = append([]string(nil), values...)
+		}
+		if ginContext != nil {
+			c.forwardHeaders(ginContext, req.Header)
+			req.Header.Set(CorrelationIdHeaderKey, ginContext.GetHeader(CorrelationIdHeaderKey))
+		}
+		if tc, ok := TraceContextFromContext(ctx); ok {
+			req.Header.Set(TraceParentHeaderKey, tc.TraceParent())
+			if tc.TraceState != "" {
+				req.Header.Set(TraceStateHeaderKey, tc.TraceState)
+			}
+		}
+		if c.tracer != nil {
+			c.tracer.Inject(ctx, req.Header)
+		}
+		if err := setRequestTimeoutHeader(ctx, req.Header); err != nil {
+			return nil, err
+		}
+
+		res, err := c.httpClient.Do(req)
+		if !retryable || attempt >= c.retryPolicy.MaxRetries || !c.retryPolicy.shouldRetry(res, err) {
+			if err != nil {
+				return nil, err
+			}
+			return res, nil
+		}
+
+		// discarding the response of the failed attempt
+		if res != nil {
+			_, _ = io.Copy(ioutil.Discard, res.Body)
+			_ = res.Body.Close()
+		}
+
+		if c.metrics != nil {
+			c.metrics.observeRetry(ctx, c.resolveUrl(url))
+		}
+
+		if err := sleepWithContext(ctx, c.retryPolicy.backoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Request sends a request of any method, the correlation id and trace context are propagated as for the other methods
+func (c *Client) Request(ctx context.Context, method string, url string, body io.Reader, opts ...RequestOption) (*http.Response, error) {
+	config, err := newRequestConfig(opts)
+	if err != nil {
+		return nil, err
 	}
 
-	res, err := client.Do(req)
+	requestUrl, err := appendQuery(url, config.query)
 	if err != nil {
 		return nil, err
 	}
 
-	return res, nil
+	return c.send(ctx, method, requestUrl, config.header, body, nil)
+}
+
+// Do sends a request built by the caller through the client, ctx is used instead of the context of req
+func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if ctx == nil {
+		ctx = req.Context()
+	}
+
+	var body io.Reader
+	if req.Body != nil && req.Body != http.NoBody {
+		body = req.Body
+	}
+	return c.send(ctx, req.Method, req.URL.String(), req.Header, body, req)
+}
+
+func (c *Client) Post(ctx context.Context, url string, contentType string, body io.Reader, opts ...RequestOption) (*http.Response, error) {
+	return c.Request(ctx, http.MethodPost, url, body, withContentType(contentType, opts)...)
+}
+
+func (c *Client) Get(ctx context.Context, url string, opts ...RequestOption) (*http.Response, error) {
+	return c.Request(ctx, http.MethodGet, url, nil, opts...)
+}
+
+func (c *Client) Head(ctx context.Context, url string, opts ...RequestOption) (*http.Response, error) {
+	return c.Request(ctx, http.MethodHead, url, nil, opts...)
+}
+
+func (c *Client) Put(ctx context.Context, url string, contentType string, body io.Reader, opts ...RequestOption) (*http.Response, error) {
+	return c.Request(ctx, http.MethodPut, url, body, withContentType(contentType, opts)...)
 }
 
-func Post(ctx context.Context, url string, contentType string, body io.Reader) (*http.Response, error) {
-	return send(ctx, http.MethodPost, url, contentType, body)
+func (c *Client) Delete(ctx context.Context, url string, contentType string, body io.Reader, opts ...RequestOption) (*http.Response, error) {
+	return c.Request(ctx, http.MethodDelete, url, body, withContentType(contentType, opts)...)
 }
 
-func Get(ctx context.Context, url string) (*http.Response, error) {
-	return send(ctx, http.MethodGet, url, "", nil)
+func (c *Client) Patch(ctx context.Context, url string, contentType string, body io.Reader, opts ...RequestOption) (*http.Response, error) {
+	return c.Request(ctx, http.MethodPatch, url, body, withContentType(contentType, opts)...)
 }
 
-func Put(ctx context.Context, url string, contentType string, body io.Reader) (*http.Response, error) {
-	return send(ctx, http.MethodPut, url, contentType, body)
+func Request(ctx context.Context, method string, url string, body io.Reader, opts ...RequestOption) (*http.Response, error) {
+	return DefaultClient.Request(ctx, method, url, body, opts...)
 }
 
-func Delete(ctx context.Context, url string, contentType string, body io.Reader) (*http.Response, error) {
-	return send(ctx, http.MethodDelete, url, contentType, body)
+func Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	return DefaultClient.Do(ctx, req)
+}
+
+func Post(ctx context.Context, url string, contentType string, body io.Reader, opts ...RequestOption) (*http.Response, error) {
+	return DefaultClient.Post(ctx, url, contentType, body, opts...)
+}
+
+func Get(ctx context.Context, url string, opts ...RequestOption) (*http.Response, error) {
+	return DefaultClient.Get(ctx, url, opts...)
+}
+
+func Head(ctx context.Context, url string, opts ...RequestOption) (*http.Response, error) {
+	return DefaultClient.Head(ctx, url, opts...)
+}
+
+func Put(ctx context.Context, url string, contentType string, body io.Reader, opts ...RequestOption) (*http.Response, error) {
+	return DefaultClient.Put(ctx, url, contentType, body, opts...)
+}
+
+func Delete(ctx context.Context, url string, contentType string, body io.Reader, opts ...RequestOption) (*http.Response, error) {
+	return DefaultClient.Delete(ctx, url, contentType, body, opts...)
+}
+
+func Patch(ctx context.Context, url string, contentType string, body io.Reader, opts ...RequestOption) (*http.Response, error) {
+	return DefaultClient.Patch(ctx, url, contentType, body, opts...)
+}
+
+type RequestOption func(config *requestConfig)
+
+type requestConfig struct {
+	header  http.Header
+	queries []interface{}
+	query   url.Values
+}
+
+func newRequestConfig(opts []RequestOption) (*requestConfig, error) {
+	config := &requestConfig{
+		header: http.Header{},
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	for _, query := range config.queries {
+		values, err := queryValues(query)
+		if err != nil {
+			return nil, err
+		}
+		if config.query == nil {
+			config.query = url.Values{}
+		}
+		for key, value := range values {
+			config.query[key] = append(config.query[key], value...)
+		}
+	}
+	return config, nil
+}
+
+func WithHeader(key string, value string) RequestOption {
+	return func(config *requestConfig) {
+		config.header.Set(key, value)
+	}
+}
+
+func WithHeaders(header http.Header) RequestOption {
+	return func(config *requestConfig) {
+		for key, values := range header {
+			for _, value := range values {
+				config.header.Add(key, value)
+			}
+		}
+	}
+}
+
+// WithQuery accepts url.Values, map[string]string, map[string][]string or a struct with form tags
+func WithQuery(query interface{}) RequestOption {
+	return func(config *requestConfig) {
+		if query != nil {
+			config.queries = append(config.queries, query)
+		}
+	}
+}
+
+// withContentType puts the content type first, so it can still be overridden by the options of the caller
+func withContentType(contentType string, opts []RequestOption) []RequestOption {
+	if contentType == "" {
+		return opts
+	}
+	return append([]RequestOption{WithHeader(ContentTypeHeaderKey, contentType)}, opts...)
+}
+
+// setRequestTimeoutHeader tells the downstream service how much of the deadline is left, in milliseconds
+func setRequestTimeoutHeader(ctx context.Context, header http.Header) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return context.DeadlineExceeded
+	}
+
+	remainingMs := int64(remaining / time.Millisecond)
+	if remainingMs < 1 {
+		remainingMs = 1
+	}
+	header.Set(RequestTimeoutHeaderKey, strconv.FormatInt(remainingMs, 10))
+	return nil
 }
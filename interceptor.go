@@ -9,6 +9,9 @@ import (
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,73 +25,208 @@ func CorrelationIdUnaryServerInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
-func LogWithCorrelationIdUnaryServerInterceptor(out io.Writer, ignoreList []string) grpc.UnaryServerInterceptor {
-	mustIgnoreLogging := func(apiName string) bool {
-		if ignoreList == nil {
-			return false
-		}
-
-		for _, ignoreItem := range ignoreList {
-			if ignoreItem == apiName {
-				return true
-			}
+func CorrelationIdStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.TrimSpace(correlationIdFromIncomingContext(ss.Context())) == "" {
+			return status.Errorf(codes.InvalidArgument, "%s is missing from metadata", CorrelationIdHeaderKey)
 		}
-		return false
+		return handler(srv, ss)
 	}
+}
+
+func LogWithCorrelationIdUnaryServerInterceptor(out io.Writer, ignoreList []string, opts ...LogOption) grpc.UnaryServerInterceptor {
+	mustIgnoreLogging := newGrpcIgnoreList(ignoreList)
+	config := newLogConfig(opts)
 
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if mustIgnoreLogging(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
 		start := time.Now()
 
-		res, handlerErr := handler(ctx, req)
-		if mustIgnoreLogging(info.FullMethod) {
-			return res, handlerErr
+		// capturing the response header metadata set by the handler
+		var transportStream *headerCaptureTransportStream
+		if len(config.responseHeaders) > 0 {
+			if ts := grpc.ServerTransportStreamFromContext(ctx); ts != nil {
+				transportStream = &headerCaptureTransportStream{ServerTransportStream: ts}
+				ctx = grpc.NewContextWithServerTransportStream(ctx, transportStream)
+			}
 		}
 
+		res, handlerErr := handler(ctx, req)
+
 		// Logging function
 		go func() {
-			// ip
-			ip := "-"
-			p, ok := peer.FromContext(ctx)
-			if ok {
-				ip = p.Addr.String()
-			}
+			// end
+			end := time.Now()
 
-			// status code
-			statusCode := "-"
-			st, ok := status.FromError(handlerErr)
-			if ok {
-				statusCode = st.Code().String()
-			}
+			// latency
+			latency := end.Sub(start)
 
-			// correlation id
-			correlationId := "-"
-			if meta, ok := metadata.FromIncomingContext(ctx); ok {
-				if correlationIds := meta.Get(CorrelationIdHeaderKey); len(correlationIds) > 0 {
-					correlationId = correlationIds[0]
-				}
+			record := LogRecord{
+				Time:          end,
+				CorrelationId: grpcCorrelationIdForLog(ctx),
+				Status:        grpcStatusCodeForLog(handlerErr),
+				Latency:       latency,
+				ClientIp:      grpcPeerIpForLog(ctx),
+				Path:          info.FullMethod,
+				Body:          grpcRequestBodyToString(req),
+			}
+			if config.logResponseBody {
+				record.ResponseBody = grpcMessageToCapturedBody(res, config.maxResponseBodySize).String()
 			}
+			if transportStream != nil {
+				record.ResponseHeaders = selectHeaders(config.responseHeaders, transportStream.get)
+			}
+
+			_, _ = fmt.Fprint(out, config.formatter.Format(record))
+		}()
+
+		return res, handlerErr
+	}
+}
+
+func LogWithCorrelationIdStreamServerInterceptor(out io.Writer, ignoreList []string, opts ...LogOption) grpc.StreamServerInterceptor {
+	mustIgnoreLogging := newGrpcIgnoreList(ignoreList)
+	config := newLogConfig(opts)
 
-			// api name
-			apiName := info.FullMethod
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if mustIgnoreLogging(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		start := time.Now()
+		ctx := ss.Context()
+
+		stream := &countingServerStream{ServerStream: ss}
+		handlerErr := handler(srv, stream)
 
+		// Logging function
+		go func() {
 			// end
 			end := time.Now()
 
 			// latency
 			latency := end.Sub(start)
 
-			_, _ = fmt.Fprint(out, formatLog(
-				end,
-				correlationId,
-				statusCode,
-				latency,
-				ip,
-				apiName,
-				grpcRequestBodyToString(req),
-			),
-			)
+			_, _ = fmt.Fprint(out, config.formatter.Format(LogRecord{
+				Time:          end,
+				CorrelationId: grpcCorrelationIdForLog(ctx),
+				Status:        grpcStatusCodeForLog(handlerErr),
+				Latency:       latency,
+				ClientIp:      grpcPeerIpForLog(ctx),
+				Path:          info.FullMethod,
+				Body:          grpcStreamSummaryToString(stream.received(), stream.sent()),
+			}))
 		}()
 
-		return res, handlerErr
+		return handlerErr
+	}
+}
+
+type countingServerStream struct {
+	grpc.ServerStream
+	receivedCount int64
+	sentCount     int64
+}
+
+func (s *countingServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		atomic.AddInt64(&s.sentCount, 1)
+	}
+	return err
+}
+
+func (s *countingServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		atomic.AddInt64(&s.receivedCount, 1)
+	}
+	return err
+}
+
+func (s *countingServerStream) received() int64 {
+	return atomic.LoadInt64(&s.receivedCount)
+}
+
+func (s *countingServerStream) sent() int64 {
+	return atomic.LoadInt64(&s.sentCount)
+}
+
+type headerCaptureTransportStream struct {
+	grpc.ServerTransportStream
+	mutex  sync.Mutex
+	header metadata.MD
+}
+
+func (s *headerCaptureTransportStream) SetHeader(md metadata.MD) error {
+	s.capture(md)
+	return s.ServerTransportStream.SetHeader(md)
+}
+
+func (s *headerCaptureTransportStream) SendHeader(md metadata.MD) error {
+	s.capture(md)
+	return s.ServerTransportStream.SendHeader(md)
+}
+
+func (s *headerCaptureTransportStream) capture(md metadata.MD) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.header = metadata.Join(s.header, md)
+}
+
+func (s *headerCaptureTransportStream) get(name string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return strings.Join(s.header.Get(name), ", ")
+}
+
+func newGrpcIgnoreList(ignoreList []string) func(apiName string) bool {
+	return func(apiName string) bool {
+		if ignoreList == nil {
+			return false
+		}
+
+		for _, ignoreItem := range ignoreList {
+			if ignoreItem == apiName {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func correlationIdFromIncomingContext(ctx context.Context) string {
+	if meta, ok := metadata.FromIncomingContext(ctx); ok {
+		if correlationIds := meta.Get(CorrelationIdHeaderKey); len(correlationIds) > 0 {
+			return correlationIds[0]
+		}
+	}
+	return ""
+}
+
+func grpcCorrelationIdForLog(ctx context.Context) string {
+	correlationId := correlationIdFromIncomingContext(ctx)
+	if correlationId == "" {
+		return "-"
+	}
+	return correlationId
+}
+
+func grpcStatusCodeForLog(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return "-"
+	}
+	return st.Code().String()
+}
+
+func grpcPeerIpForLog(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "-"
 	}
+	return p.Addr.String()
 }
@@ -1,8 +1,8 @@
 package ginney
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -41,7 +41,7 @@ func TestLogWithCorrelationIdUnaryServerInterceptor(t *testing.T) {
 	info := grpc.UnaryServerInfo{FullMethod: "randomMethod"}
 
 	t.Run("Happy", func(t *testing.T) {
-		buffer := new(bytes.Buffer)
+		buffer := new(syncBuffer)
 
 		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CorrelationIdHeaderKey, "random-uuid"))
 		interceptor := LogWithCorrelationIdUnaryServerInterceptor(buffer, nil)
@@ -61,7 +61,7 @@ func TestLogWithCorrelationIdUnaryServerInterceptor(t *testing.T) {
 	})
 
 	t.Run("Happy, no log when api name is in ignore list", func(t *testing.T) {
-		buffer := new(bytes.Buffer)
+		buffer := new(syncBuffer)
 
 		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CorrelationIdHeaderKey, "random-uuid"))
 		interceptor := LogWithCorrelationIdUnaryServerInterceptor(buffer, []string{"randomMethod"})
@@ -77,7 +77,7 @@ func TestLogWithCorrelationIdUnaryServerInterceptor(t *testing.T) {
 	})
 
 	t.Run("Happy, handler returns error", func(t *testing.T) {
-		buffer := new(bytes.Buffer)
+		buffer := new(syncBuffer)
 
 		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CorrelationIdHeaderKey, "random-uuid"))
 		interceptor := LogWithCorrelationIdUnaryServerInterceptor(buffer, nil)
@@ -95,4 +95,141 @@ func TestLogWithCorrelationIdUnaryServerInterceptor(t *testing.T) {
 		assert.Equal(t, `{"id":"1"}`+"\n", payload)
 		assert.Equal(t, "random-uuid", correlationId)
 	})
+	t.Run("Happy, JSON log formatter", func(t *testing.T) {
+		buffer := new(syncBuffer)
+
+		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CorrelationIdHeaderKey, "random-uuid"))
+		interceptor := LogWithCorrelationIdUnaryServerInterceptor(buffer, nil, WithLogFormatter(JSONLogFormatter{}))
+		_, err := interceptor(incomingCtx, req, &info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		assert.NoError(t, err)
+
+		// Wait for go routine in log
+		time.Sleep(1 * time.Second)
+
+		var line map[string]interface{}
+		err = json.Unmarshal(buffer.Bytes(), &line)
+		assert.NoError(t, err)
+		assert.Equal(t, "random-uuid", line["correlationId"])
+		assert.Equal(t, codes.OK.String(), line["status"])
+		assert.Equal(t, "randomMethod", line["path"])
+		assert.Equal(t, map[string]interface{}{"id": "1"}, line["body"])
+	})
+
+	t.Run("Happy, response body and headers are logged when enabled", func(t *testing.T) {
+		buffer := new(syncBuffer)
+
+		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CorrelationIdHeaderKey, "random-uuid"))
+		transportStream := &fakeServerTransportStream{}
+		incomingCtx = grpc.NewContextWithServerTransportStream(incomingCtx, transportStream)
+
+		interceptor := LogWithCorrelationIdUnaryServerInterceptor(buffer, nil,
+			WithResponseBodyLogging(0),
+			WithResponseHeaderLogging("x-ratelimit-remaining"),
+		)
+		_, err := interceptor(incomingCtx, req, &info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			_ = grpc.SetHeader(ctx, metadata.Pairs("x-ratelimit-remaining", "99"))
+			return map[string]interface{}{"privateKey": "secret", "id": "1"}, nil
+		})
+		assert.NoError(t, err)
+
+		// Wait for go routine in log
+		time.Sleep(1 * time.Second)
+
+		responseBody, responseHeaders := extractResponseLogMessage(buffer.String())
+		assert.Equal(t, `{"id":"1","privateKey":"[HIDDEN_FIELD]"}`, responseBody)
+		assert.Equal(t, `{"x-ratelimit-remaining":"99"}`, responseHeaders)
+		assert.Equal(t, []string{"99"}, transportStream.header.Get("x-ratelimit-remaining"))
+	})
+}
+
+func TestCorrelationIdStreamServerInterceptor(t *testing.T) {
+	info := grpc.StreamServerInfo{}
+
+	t.Run("Happy", func(t *testing.T) {
+		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CorrelationIdHeaderKey, "random-uuid"))
+		interceptor := CorrelationIdStreamServerInterceptor()
+		err := interceptor(nil, newFakeServerStream(incomingCtx), &info, func(srv interface{}, stream grpc.ServerStream) error {
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error, correlation id is not found in the metadata", func(t *testing.T) {
+		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs("random-key", "random-value"))
+		interceptor := CorrelationIdStreamServerInterceptor()
+		err := interceptor(nil, newFakeServerStream(incomingCtx), &info, func(srv interface{}, stream grpc.ServerStream) error {
+			return nil
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestLogWithCorrelationIdStreamServerInterceptor(t *testing.T) {
+	info := grpc.StreamServerInfo{FullMethod: "randomStreamMethod"}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		for {
+			if err := stream.RecvMsg(nil); err != nil {
+				break
+			}
+			if err := stream.SendMsg("pong"); err != nil {
+				return err
+			}
+		}
+		return stream.SendMsg("done")
+	}
+
+	t.Run("Happy", func(t *testing.T) {
+		buffer := new(syncBuffer)
+
+		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CorrelationIdHeaderKey, "random-uuid"))
+		interceptor := LogWithCorrelationIdStreamServerInterceptor(buffer, nil)
+		err := interceptor(nil, newFakeServerStream(incomingCtx, "ping", "ping"), &info, handler)
+		assert.NoError(t, err)
+
+		// Wait for go routine in log
+		time.Sleep(1 * time.Second)
+
+		correlationId, statusCode, apiName, payload := extractLogMessage(buffer.String())
+		assert.Equal(t, codes.OK.String(), statusCode)
+		assert.Equal(t, "randomStreamMethod", apiName)
+		assert.Equal(t, `{"received":2,"sent":3}`+"\n", payload)
+		assert.Equal(t, "random-uuid", correlationId)
+	})
+
+	t.Run("Happy, no log when api name is in ignore list", func(t *testing.T) {
+		buffer := new(syncBuffer)
+
+		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CorrelationIdHeaderKey, "random-uuid"))
+		interceptor := LogWithCorrelationIdStreamServerInterceptor(buffer, []string{"randomStreamMethod"})
+		err := interceptor(nil, newFakeServerStream(incomingCtx, "ping"), &info, handler)
+		assert.NoError(t, err)
+
+		// Wait for go routine in log
+		time.Sleep(1 * time.Second)
+
+		assert.Equal(t, "", buffer.String())
+	})
+
+	t.Run("Happy, handler returns error", func(t *testing.T) {
+		buffer := new(syncBuffer)
+
+		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CorrelationIdHeaderKey, "random-uuid"))
+		interceptor := LogWithCorrelationIdStreamServerInterceptor(buffer, nil)
+		err := interceptor(nil, newFakeServerStream(incomingCtx, "ping"), &info, func(srv interface{}, stream grpc.ServerStream) error {
+			_ = stream.RecvMsg(nil)
+			return status.Error(codes.Aborted, "")
+		})
+		assert.Error(t, err)
+
+		// Wait for go routine in log
+		time.Sleep(1 * time.Second)
+
+		correlationId, statusCode, apiName, payload := extractLogMessage(buffer.String())
+		assert.Equal(t, codes.Aborted.String(), statusCode)
+		assert.Equal(t, "randomStreamMethod", apiName)
+		assert.Equal(t, `{"received":1,"sent":0}`+"\n", payload)
+		assert.Equal(t, "random-uuid", correlationId)
+	})
 }
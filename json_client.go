@@ -0,0 +1,179 @@
+package ginney
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// sendJSON marshals in as the body when it is not nil and decodes the ginney envelope of the response into out
+func (c *Client) sendJSON(ctx context.Context, method string, url string, query interface{}, in interface{}, out interface{}, opts []RequestOption) error {
+	jsonOpts := []RequestOption{
+		WithHeader(AcceptHeaderKey, ContentTypeJSON),
+		WithQuery(query),
+	}
+
+	var body io.Reader
+	if in != nil {
+		jsonBytes, err := json.Marshal(in)
+		if err != nil {
+			return errors.Wrap(err, "fail to encode request body")
+		}
+		body = bytes.NewReader(jsonBytes)
+		jsonOpts = append(jsonOpts, WithHeader(ContentTypeHeaderKey, ContentTypeJSON))
+	}
+
+	res, err := c.Request(ctx, method, url, body, append(jsonOpts, opts...)...)
+	if err != nil {
+		return err
+	}
+	return DecodeEnvelope(res, out)
+}
+
+func (c *Client) GetJSON(ctx context.Context, url string, query interface{}, out interface{}, opts ...RequestOption) error {
+	return c.sendJSON(ctx, http.MethodGet, url, query, nil, out, opts)
+}
+
+func (c *Client) PostJSON(ctx context.Context, url string, query interface{}, in interface{}, out interface{}, opts ...RequestOption) error {
+	return c.sendJSON(ctx, http.MethodPost, url, query, in, out, opts)
+}
+
+func (c *Client) PutJSON(ctx context.Context, url string, query interface{}, in interface{}, out interface{}, opts ...RequestOption) error {
+	return c.sendJSON(ctx, http.MethodPut, url, query, in, out, opts)
+}
+
+func (c *Client) PatchJSON(ctx context.Context, url string, query interface{}, in interface{}, out interface{}, opts ...RequestOption) error {
+	return c.sendJSON(ctx, http.MethodPatch, url, query, in, out, opts)
+}
+
+func GetJSON(ctx context.Context, url string, query interface{}, out interface{}, opts ...RequestOption) error {
+	return DefaultClient.GetJSON(ctx, url, query, out, opts...)
+}
+
+func PostJSON(ctx context.Context, url string, query interface{}, in interface{}, out interface{}, opts ...RequestOption) error {
+	return DefaultClient.PostJSON(ctx, url, query, in, out, opts...)
+}
+
+func PutJSON(ctx context.Context, url string, query interface{}, in interface{}, out interface{}, opts ...RequestOption) error {
+	return DefaultClient.PutJSON(ctx, url, query, in, out, opts...)
+}
+
+func PatchJSON(ctx context.Context, url string, query interface{}, in interface{}, out interface{}, opts ...RequestOption) error {
+	return DefaultClient.PatchJSON(ctx, url, query, in, out, opts...)
+}
+
+func appendQuery(rawUrl string, query interface{}) (string, error) {
+	values, err := queryValues(query)
+	if err != nil || len(values) == 0 {
+		return rawUrl, err
+	}
+
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+
+	merged := parsedUrl.Query()
+	for key, value := range values {
+		merged[key] = append(merged[key], value...)
+	}
+	parsedUrl.RawQuery = merged.Encode()
+	return parsedUrl.String(), nil
+}
+
+// queryValues accepts url.Values, map[string]string, map[string][]string or a struct with form tags
+func queryValues(query interface{}) (url.Values, error) {
+	switch query := query.(type) {
+	case nil:
+		return nil, nil
+	case url.Values:
+		return query, nil
+	case map[string][]string:
+		return query, nil
+	case map[string]string:
+		values := make(url.Values, len(query))
+		for key, value := range query {
+			values.Set(key, value)
+		}
+		return values, nil
+	}
+
+	value := reflect.ValueOf(query)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported query type %T", query)
+	}
+
+	values := url.Values{}
+	structToQueryValues(value, values)
+	return values, nil
+}
+
+func structToQueryValues(value reflect.Value, values url.Values) {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+
+		tag := strings.Split(field.Tag.Get("form"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		omitEmpty := len(tag) > 1 && tag[1] == "omitempty"
+
+		fieldValue := value.Field(i)
+		for fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				break
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		if fieldValue.Kind() == reflect.Ptr {
+			continue
+		}
+
+		// embedded structs without a tag share the query of their parent, also when the struct type is unexported
+		if field.Anonymous && name == "" && fieldValue.Kind() == reflect.Struct {
+			structToQueryValues(fieldValue, values)
+			continue
+		}
+		// any other unexported field cannot be read, including an embedded non-struct type such as struct{ myInt }
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if omitEmpty && fieldValue.IsZero() {
+			continue
+		}
+
+		if fieldValue.Kind() == reflect.Slice || fieldValue.Kind() == reflect.Array {
+			for j := 0; j < fieldValue.Len(); j++ {
+				values.Add(name, queryValueString(fieldValue.Index(j)))
+			}
+			continue
+		}
+		values.Add(name, queryValueString(fieldValue))
+	}
+}
+
+func queryValueString(value reflect.Value) string {
+	if t, ok := value.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(value.Interface())
+}
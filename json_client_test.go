@@ -0,0 +1,153 @@
+package ginney
+
+import (
+	"context"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type capturedJSONRequest struct {
+	method string
+	url    string
+	header http.Header
+	body   string
+}
+
+func initJSONHttpMock(statusCode int, fixture string) *capturedJSONRequest {
+	captured := &capturedJSONRequest{}
+	httpmock.Activate()
+	responder := func(req *http.Request) (*http.Response, error) {
+		captured.method = req.Method
+		captured.url = req.URL.String()
+		captured.header = req.Header
+		if req.Body != nil {
+			body, _ := ioutil.ReadAll(req.Body)
+			captured.body = string(body)
+		}
+		return httpmock.NewStringResponse(statusCode, fixture), nil
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch} {
+		httpmock.RegisterResponder(method, `=~^https://www\.fcuk\.com/accounts`, responder)
+	}
+	return captured
+}
+
+func TestClient_JSON(t *testing.T) {
+	type account struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	t.Run("Happy - GetJSON with struct query keeps correlation id", func(t *testing.T) {
+		captured := initJSONHttpMock(http.StatusOK, `{"status":"success","message":"OK","data":{"id":"1","name":"a"}}`)
+		type accountQuery struct {
+			Ids     []string  `form:"id"`
+			Status  string    `form:"status,omitempty"`
+			Since   time.Time `form:"since"`
+			Ignored string    `form:"-"`
+			Limit   *int      `form:"limit"`
+		}
+
+		gc := createGinContextWithCorrelationId(http.MethodGet, "/chaiyawatkit", "random-uuid")
+		ctx := context.WithValue(context.TODO(), GinContextKey, gc)
+
+		var out account
+		err := GetJSON(ctx, "https://www.fcuk.com/accounts?sort=name", accountQuery{
+			Ids:     []string{"1", "2"},
+			Since:   time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+			Ignored: "x",
+		}, &out)
+
+		assert.NoError(t, err)
+		assert.Equal(t, account{Id: "1", Name: "a"}, out)
+		assert.Equal(t, http.MethodGet, captured.method)
+		assert.Equal(t, "https://www.fcuk.com/accounts?id=1&id=2&since=2021-01-02T03%3A04%3A05Z&sort=name", captured.url)
+		assert.Equal(t, ContentTypeJSON, captured.header.Get(AcceptHeaderKey))
+		assert.Equal(t, "", captured.header.Get(ContentTypeHeaderKey))
+		assert.Equal(t, "random-uuid", captured.header.Get(CorrelationIdHeaderKey))
+	})
+
+	t.Run("Happy - PostJSON marshals the body with map query", func(t *testing.T) {
+		captured := initJSONHttpMock(http.StatusCreated, `{"status":"success","message":"OK","data":{"id":"2","name":"b"}}`)
+
+		var out account
+		err := NewClient(WithBaseUrl("https://www.fcuk.com")).PostJSON(context.TODO(), "/accounts", map[string]string{"dryRun": "true"}, account{Name: "b"}, &out)
+
+		assert.NoError(t, err)
+		assert.Equal(t, account{Id: "2", Name: "b"}, out)
+		assert.Equal(t, http.MethodPost, captured.method)
+		assert.Equal(t, "https://www.fcuk.com/accounts?dryRun=true", captured.url)
+		assert.Equal(t, `{"id":"","name":"b"}`, captured.body)
+		assert.Equal(t, ContentTypeJSON, captured.header.Get(ContentTypeHeaderKey))
+	})
+
+	t.Run("Happy - PutJSON and PatchJSON", func(t *testing.T) {
+		captured := initJSONHttpMock(http.StatusOK, `{"status":"success","message":"OK","data":null}`)
+
+		assert.NoError(t, PutJSON(context.TODO(), "https://www.fcuk.com/accounts/1", url.Values{"v": []string{"1"}}, account{Name: "c"}, nil))
+		assert.Equal(t, http.MethodPut, captured.method)
+		assert.Equal(t, "https://www.fcuk.com/accounts/1?v=1", captured.url)
+
+		assert.NoError(t, PatchJSON(context.TODO(), "https://www.fcuk.com/accounts/1", nil, map[string]string{"name": "d"}, nil))
+		assert.Equal(t, http.MethodPatch, captured.method)
+		assert.Equal(t, `{"name":"d"}`, captured.body)
+	})
+
+	t.Run("Happy - PutJSON against 204 No Content", func(t *testing.T) {
+		initJSONHttpMock(http.StatusNoContent, ``)
+
+		var out account
+		assert.NoError(t, PutJSON(context.TODO(), "https://www.fcuk.com/accounts/1", nil, account{Name: "c"}, &out))
+		assert.Equal(t, account{}, out)
+	})
+
+	t.Run("Error - fail envelope", func(t *testing.T) {
+		initJSONHttpMock(http.StatusNotFound, `{"status":"fail","code":"ACC-404","message":"account not found"}`)
+
+		err := GetJSON(context.TODO(), "https://www.fcuk.com/accounts/9", nil, nil)
+
+		assert.Equal(t, http.StatusNotFound, AsError(err).HttpStatus)
+		assert.Equal(t, "ACC-404", AsError(err).Code)
+	})
+
+	t.Run("Happy - unexported embedded types in struct query", func(t *testing.T) {
+		captured := initJSONHttpMock(http.StatusOK, `{"status":"success","message":"OK"}`)
+
+		assert.NoError(t, GetJSON(context.TODO(), "https://www.fcuk.com/accounts", embeddingQuery{
+			queryInt:    1,
+			queryFilter: queryFilter{Status: "active", hidden: "x"},
+			Name:        "a",
+		}, nil))
+		assert.Equal(t, "https://www.fcuk.com/accounts?name=a&status=active", captured.url)
+	})
+
+	t.Run("Error - unsupported query", func(t *testing.T) {
+		err := GetJSON(context.TODO(), "https://www.fcuk.com/accounts", 1, nil)
+
+		assert.EqualError(t, err, "unsupported query type int")
+	})
+
+	t.Run("Error - body cannot be marshalled", func(t *testing.T) {
+		err := PostJSON(context.TODO(), "https://www.fcuk.com/accounts", nil, make(chan int), nil)
+
+		assert.Error(t, err)
+	})
+}
+
+type queryInt int
+
+type queryFilter struct {
+	Status string `form:"status"`
+	hidden string
+}
+
+type embeddingQuery struct {
+	queryInt
+	queryFilter
+	Name string `form:"name"`
+}
@@ -0,0 +1,152 @@
+package ginney
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type LogRecord struct {
+	Time          time.Time
+	CorrelationId string
+	Status        string
+	Latency       time.Duration
+	ClientIp      string
+	Method        string
+	Path          string
+	Body          string
+
+	ResponseBody    string
+	ResponseHeaders map[string]string
+}
+
+type LogFormatter interface {
+	Format(record LogRecord) string
+}
+
+type TextLogFormatter struct{}
+
+func (TextLogFormatter) Format(record LogRecord) string {
+	apiName := record.Path
+	if record.Method != "" {
+		apiName = fmt.Sprintf("%-7s %s", record.Method, record.Path)
+	}
+
+	// response columns are appended only when response logging is enabled, so the default line keeps its shape
+	body := record.Body
+	if record.ResponseBody != "" || len(record.ResponseHeaders) > 0 {
+		responseHeaders, _ := json.Marshal(record.ResponseHeaders)
+		body = fmt.Sprintf("%s | %s | %s", record.Body, record.ResponseBody, responseHeaders)
+	}
+
+	return formatLog(
+		record.Time,
+		record.CorrelationId,
+		record.Status,
+		record.Latency,
+		record.ClientIp,
+		apiName,
+		body,
+	)
+}
+
+type JSONLogFormatter struct{}
+
+type jsonLogLine struct {
+	Time          string      `json:"time"`
+	CorrelationId string      `json:"correlationId"`
+	Status        string      `json:"status"`
+	LatencyMs     float64     `json:"latencyMs"`
+	ClientIp      string      `json:"clientIp"`
+	Method        string      `json:"method"`
+	Path          string      `json:"path"`
+	Body          interface{} `json:"body"`
+
+	ResponseBody    interface{}       `json:"responseBody,omitempty"`
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+}
+
+func (JSONLogFormatter) Format(record LogRecord) string {
+	line := jsonLogLine{
+		Time:          record.Time.Format(time.RFC3339Nano),
+		CorrelationId: record.CorrelationId,
+		Status:        record.Status,
+		LatencyMs:     float64(record.Latency) / float64(time.Millisecond),
+		ClientIp:      record.ClientIp,
+		Method:        record.Method,
+		Path:          record.Path,
+		Body:          logBodyToJSONValue(record.Body),
+
+		ResponseHeaders: record.ResponseHeaders,
+	}
+	if record.ResponseBody != "" {
+		line.ResponseBody = logBodyToJSONValue(record.ResponseBody)
+	}
+
+	jsonBytes, _ := json.Marshal(line)
+	return string(jsonBytes) + "\n"
+}
+
+func logBodyToJSONValue(body string) interface{} {
+	// keeping the censored json body as an object instead of an escaped string
+	if body != "" && json.Valid([]byte(body)) {
+		return json.RawMessage(body)
+	}
+	return body
+}
+
+type LogOption func(config *logConfig)
+
+type logConfig struct {
+	formatter   LogFormatter
+	maxBodySize int64
+
+	logResponseBody     bool
+	maxResponseBodySize int64
+	responseHeaders     []string
+}
+
+func newLogConfig(opts []LogOption) *logConfig {
+	config := &logConfig{
+		formatter:   TextLogFormatter{},
+		maxBodySize: DefaultMaxBodyLogSize,
+
+		maxResponseBodySize: DefaultMaxBodyLogSize,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+func WithLogFormatter(formatter LogFormatter) LogOption {
+	return func(config *logConfig) {
+		if formatter != nil {
+			config.formatter = formatter
+		}
+	}
+}
+
+// WithMaxBodyLogSize limits the request body read for the log, 0 leaves the body out of the log
+func WithMaxBodyLogSize(maxBodySize int64) LogOption {
+	return func(config *logConfig) {
+		if maxBodySize >= 0 {
+			config.maxBodySize = maxBodySize
+		}
+	}
+}
+
+func WithResponseBodyLogging(maxResponseBodySize int64) LogOption {
+	return func(config *logConfig) {
+		config.logResponseBody = true
+		if maxResponseBodySize > 0 {
+			config.maxResponseBodySize = maxResponseBodySize
+		}
+	}
+}
+
+func WithResponseHeaderLogging(headerNames ...string) LogOption {
+	return func(config *logConfig) {
+		config.responseHeaders = append(config.responseHeaders, headerNames...)
+	}
+}
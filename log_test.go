@@ -0,0 +1,82 @@
+package ginney
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTextLogFormatter_Format(t *testing.T) {
+	eventTime := time.Date(2021, 11, 1, 10, 30, 0, 0, time.UTC)
+
+	t.Run("Happy - http record", func(t *testing.T) {
+		record := LogRecord{
+			Time:          eventTime,
+			CorrelationId: "random-uuid",
+			Status:        "200",
+			Latency:       time.Millisecond,
+			ClientIp:      "127.0.0.1",
+			Method:        "GET",
+			Path:          "/random",
+			Body:          "{}",
+		}
+
+		assert.Equal(t,
+			formatLog(eventTime, "random-uuid", "200", time.Millisecond, "127.0.0.1", "GET     /random", "{}"),
+			TextLogFormatter{}.Format(record),
+		)
+	})
+
+	t.Run("Happy - grpc record without method", func(t *testing.T) {
+		record := LogRecord{
+			Time:          eventTime,
+			CorrelationId: "random-uuid",
+			Status:        "OK",
+			Latency:       time.Millisecond,
+			ClientIp:      "127.0.0.1",
+			Path:          "/random.Service/Method",
+			Body:          "{}",
+		}
+
+		correlationId, statusCode, apiName, payload := extractLogMessage(TextLogFormatter{}.Format(record))
+		assert.Equal(t, "random-uuid", correlationId)
+		assert.Equal(t, "OK", statusCode)
+		assert.Equal(t, "/random.Service/Method", apiName)
+		assert.Equal(t, "{}\n", payload)
+	})
+}
+
+func TestJSONLogFormatter_Format(t *testing.T) {
+	eventTime := time.Date(2021, 11, 1, 10, 30, 0, 0, time.UTC)
+
+	t.Run("Happy - json body is kept as an object", func(t *testing.T) {
+		record := LogRecord{
+			Time:          eventTime,
+			CorrelationId: "random-uuid",
+			Status:        "200",
+			Latency:       1500 * time.Microsecond,
+			ClientIp:      "127.0.0.1",
+			Method:        "POST",
+			Path:          "/random",
+			Body:          `{"example":"hello"}`,
+		}
+
+		assert.Equal(t,
+			`{"time":"2021-11-01T10:30:00Z","correlationId":"random-uuid","status":"200","latencyMs":1.5,"clientIp":"127.0.0.1","method":"POST","path":"/random","body":{"example":"hello"}}`+"\n",
+			JSONLogFormatter{}.Format(record),
+		)
+	})
+
+	t.Run("Happy - non json body is kept as a string", func(t *testing.T) {
+		record := LogRecord{
+			Time: eventTime,
+			Body: "not a json",
+		}
+
+		var line map[string]interface{}
+		err := json.Unmarshal([]byte(JSONLogFormatter{}.Format(record)), &line)
+		assert.NoError(t, err)
+		assert.Equal(t, "not a json", line["body"])
+	})
+}
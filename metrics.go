@@ -0,0 +1,286 @@
+package ginney
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"strconv"
+	"time"
+)
+
+const (
+	unmatchedRouteLabel = "unmatched"
+)
+
+type Metrics struct {
+	gatherer prometheus.Gatherer
+
+	httpRequestsTotal    *prometheus.CounterVec
+	httpRequestDuration  *prometheus.HistogramVec
+	httpRequestsInFlight prometheus.Gauge
+	httpRequestSize      *prometheus.HistogramVec
+	httpResponseSize     *prometheus.HistogramVec
+
+	grpcRequestsTotal    *prometheus.CounterVec
+	grpcRequestDuration  *prometheus.HistogramVec
+	grpcRequestsInFlight prometheus.Gauge
+	grpcRequestSize      *prometheus.HistogramVec
+	grpcResponseSize     *prometheus.HistogramVec
+}
+
+type MetricsOption func(config *metricsConfig)
+
+type metricsConfig struct {
+	namespace       string
+	durationBuckets []float64
+	sizeBuckets     []float64
+	registry        *prometheus.Registry
+}
+
+func WithMetricsNamespace(namespace string) MetricsOption {
+	return func(config *metricsConfig) {
+		config.namespace = namespace
+	}
+}
+
+func WithDurationBuckets(buckets []float64) MetricsOption {
+	return func(config *metricsConfig) {
+		config.durationBuckets = buckets
+	}
+}
+
+func WithSizeBuckets(buckets []float64) MetricsOption {
+	return func(config *metricsConfig) {
+		config.sizeBuckets = buckets
+	}
+}
+
+func WithMetricsRegistry(registry *prometheus.Registry) MetricsOption {
+	return func(config *metricsConfig) {
+		config.registry = registry
+	}
+}
+
+func NewMetrics(opts ...MetricsOption) *Metrics {
+	config := &metricsConfig{
+		durationBuckets: prometheus.DefBuckets,
+		sizeBuckets:     prometheus.ExponentialBuckets(128, 4, 8),
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	var registerer prometheus.Registerer = prometheus.DefaultRegisterer
+	var gatherer prometheus.Gatherer = prometheus.DefaultGatherer
+	if config.registry != nil {
+		registerer = config.registry
+		gatherer = config.registry
+	}
+
+	m := &Metrics{
+		gatherer: gatherer,
+
+		httpRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.namespace,
+			Name:      "http_server_requests_total",
+			Help:      "Total number of HTTP requests by route template, method and status code.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.namespace,
+			Name:      "http_server_request_duration_seconds",
+			Help:      "Duration of HTTP requests by route template, method and status code.",
+			Buckets:   config.durationBuckets,
+		}, []string{"method", "route", "status"}),
+		httpRequestsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: config.namespace,
+			Name:      "http_server_requests_in_flight",
+			Help:      "Number of HTTP requests being served.",
+		}),
+		httpRequestSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.namespace,
+			Name:      "http_server_request_size_bytes",
+			Help:      "Size of HTTP request bodies by route template and method.",
+			Buckets:   config.sizeBuckets,
+		}, []string{"method", "route"}),
+		httpResponseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.namespace,
+			Name:      "http_server_response_size_bytes",
+			Help:      "Size of HTTP response bodies by route template and method.",
+			Buckets:   config.sizeBuckets,
+		}, []string{"method", "route"}),
+
+		grpcRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.namespace,
+			Name:      "grpc_server_handled_total",
+			Help:      "Total number of gRPC calls by method and status code.",
+		}, []string{"method", "code"}),
+		grpcRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.namespace,
+			Name:      "grpc_server_handling_seconds",
+			Help:      "Duration of gRPC calls by method and status code.",
+			Buckets:   config.durationBuckets,
+		}, []string{"method", "code"}),
+		grpcRequestsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: config.namespace,
+			Name:      "grpc_server_calls_in_flight",
+			Help:      "Number of gRPC calls being served.",
+		}),
+		grpcRequestSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.namespace,
+			Name:      "grpc_server_msg_received_size_bytes",
+			Help:      "Size of received gRPC messages by method.",
+			Buckets:   config.sizeBuckets,
+		}, []string{"method"}),
+		grpcResponseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.namespace,
+			Name:      "grpc_server_msg_sent_size_bytes",
+			Help:      "Size of sent gRPC messages by method.",
+			Buckets:   config.sizeBuckets,
+		}, []string{"method"}),
+	}
+
+	registerer.MustRegister(
+		m.httpRequestsTotal,
+		m.httpRequestDuration,
+		m.httpRequestsInFlight,
+		m.httpRequestSize,
+		m.httpResponseSize,
+		m.grpcRequestsTotal,
+		m.grpcRequestDuration,
+		m.grpcRequestsInFlight,
+		m.grpcRequestSize,
+		m.grpcResponseSize,
+	)
+
+	return m
+}
+
+func (m *Metrics) Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(m.gatherer, promhttp.HandlerOpts{}))
+}
+
+func (m *Metrics) Middleware(notMeasured []string) gin.HandlerFunc {
+	var skip map[string]struct{}
+
+	if length := len(notMeasured); length > 0 {
+		skip = make(map[string]struct{}, length)
+
+		for _, path := range notMeasured {
+			skip[path] = struct{}{}
+		}
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := skip[c.Request.URL.Path]; ok {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		m.httpRequestsInFlight.Inc()
+		defer m.httpRequestsInFlight.Dec()
+
+		c.Next()
+
+		// using the route template instead of the raw path to keep the label cardinality bounded
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRouteLabel
+		}
+		method := c.Request.Method
+		statusCode := strconv.Itoa(c.Writer.Status())
+
+		m.httpRequestsTotal.WithLabelValues(method, route, statusCode).Inc()
+		m.httpRequestDuration.WithLabelValues(method, route, statusCode).Observe(time.Since(start).Seconds())
+
+		if c.Request.ContentLength >= 0 {
+			m.httpRequestSize.WithLabelValues(method, route).Observe(float64(c.Request.ContentLength))
+		}
+		if size := c.Writer.Size(); size >= 0 {
+			m.httpResponseSize.WithLabelValues(method, route).Observe(float64(size))
+		}
+	}
+}
+
+func (m *Metrics) UnaryServerInterceptor(ignoreList []string) grpc.UnaryServerInterceptor {
+	mustIgnore := newGrpcIgnoreList(ignoreList)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if mustIgnore(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		m.grpcRequestsInFlight.Inc()
+		defer m.grpcRequestsInFlight.Dec()
+
+		res, handlerErr := handler(ctx, req)
+
+		m.observeGrpc(info.FullMethod, handlerErr, start)
+		m.observeGrpcMessageSize(m.grpcRequestSize, info.FullMethod, req)
+		if handlerErr == nil {
+			m.observeGrpcMessageSize(m.grpcResponseSize, info.FullMethod, res)
+		}
+
+		return res, handlerErr
+	}
+}
+
+func (m *Metrics) StreamServerInterceptor(ignoreList []string) grpc.StreamServerInterceptor {
+	mustIgnore := newGrpcIgnoreList(ignoreList)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if mustIgnore(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		start := time.Now()
+		m.grpcRequestsInFlight.Inc()
+		defer m.grpcRequestsInFlight.Dec()
+
+		handlerErr := handler(srv, &measuringServerStream{ServerStream: ss, metrics: m, method: info.FullMethod})
+
+		m.observeGrpc(info.FullMethod, handlerErr, start)
+
+		return handlerErr
+	}
+}
+
+func (m *Metrics) observeGrpc(method string, err error, start time.Time) {
+	code := status.Code(err).String()
+
+	m.grpcRequestsTotal.WithLabelValues(method, code).Inc()
+	m.grpcRequestDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) observeGrpcMessageSize(histogram *prometheus.HistogramVec, method string, message interface{}) {
+	if protoMessage, ok := message.(proto.Message); ok {
+		histogram.WithLabelValues(method).Observe(float64(proto.Size(protoMessage)))
+	}
+}
+
+type measuringServerStream struct {
+	grpc.ServerStream
+	metrics *Metrics
+	method  string
+}
+
+func (s *measuringServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.metrics.observeGrpcMessageSize(s.metrics.grpcResponseSize, s.method, m)
+	}
+	return err
+}
+
+func (s *measuringServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.metrics.observeGrpcMessageSize(s.metrics.grpcRequestSize, s.method, m)
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package ginney
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"testing"
+)
+
+func TestMetrics_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Happy - requests are measured by route template", func(t *testing.T) {
+		metrics := NewMetrics(WithMetricsRegistry(prometheus.NewRegistry()))
+
+		router := gin.New()
+		router.Use(metrics.Middleware([]string{"/health"}))
+		router.GET("/metrics", metrics.Handler())
+		router.GET("/health", func(c *gin.Context) {
+			c.AbortWithStatus(http.StatusOK)
+		})
+		router.GET("/users/:id", func(c *gin.Context) {
+			c.String(http.StatusOK, "hello")
+		})
+
+		_ = performRequest(router, "GET", "/users/1", nil)
+		_ = performRequest(router, "GET", "/users/2", nil)
+		_ = performRequest(router, "GET", "/health", nil)
+		_ = performRequest(router, "GET", "/not-found", nil)
+
+		assert.Equal(t, float64(2), testutil.ToFloat64(metrics.httpRequestsTotal.WithLabelValues(http.MethodGet, "/users/:id", "200")))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.httpRequestsTotal.WithLabelValues(http.MethodGet, unmatchedRouteLabel, "404")))
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.httpRequestsInFlight))
+
+		resp := performRequest(router, "GET", "/metrics", nil)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `http_server_request_duration_seconds_count{method="GET",route="/users/:id",status="200"} 2`)
+		assert.Contains(t, resp.Body.String(), `http_server_response_size_bytes_sum{method="GET",route="/users/:id"} 10`)
+		assert.NotContains(t, resp.Body.String(), `route="/health"`)
+	})
+
+	t.Run("Happy - namespace and buckets are configurable", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		metrics := NewMetrics(
+			WithMetricsRegistry(registry),
+			WithMetricsNamespace("wallet"),
+			WithDurationBuckets([]float64{0.5, 1}),
+		)
+
+		router := gin.New()
+		router.Use(metrics.Middleware(nil))
+		router.GET("/random", func(c *gin.Context) {
+			c.AbortWithStatus(http.StatusOK)
+		})
+
+		_ = performRequest(router, "GET", "/random", nil)
+
+		count, err := testutil.GatherAndCount(registry, "wallet_http_server_requests_total")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		families, err := registry.Gather()
+		assert.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() == "wallet_http_server_request_duration_seconds" {
+				buckets := family.GetMetric()[0].GetHistogram().GetBucket()
+				assert.Len(t, buckets, 2)
+				assert.Equal(t, 0.5, buckets[0].GetUpperBound())
+			}
+		}
+	})
+}
+
+func TestMetrics_UnaryServerInterceptor(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		metrics := NewMetrics(WithMetricsRegistry(prometheus.NewRegistry()))
+
+		interceptor := metrics.UnaryServerInterceptor([]string{"/grpc.health.v1.Health/Check"})
+		info := grpc.UnaryServerInfo{FullMethod: "/random.Service/Method"}
+		_, _ = interceptor(context.TODO(), nil, &info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		_, _ = interceptor(context.TODO(), nil, &info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "")
+		})
+		_, _ = interceptor(context.TODO(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.grpcRequestsTotal.WithLabelValues("/random.Service/Method", codes.OK.String())))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.grpcRequestsTotal.WithLabelValues("/random.Service/Method", codes.NotFound.String())))
+		assert.Equal(t, 2, testutil.CollectAndCount(metrics.grpcRequestsTotal))
+	})
+}
+
+func TestMetrics_StreamServerInterceptor(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		metrics := NewMetrics(WithMetricsRegistry(prometheus.NewRegistry()))
+
+		interceptor := metrics.StreamServerInterceptor(nil)
+		err := interceptor(nil, newFakeServerStream(context.TODO()), &grpc.StreamServerInfo{FullMethod: "/random.Service/Stream"}, func(srv interface{}, stream grpc.ServerStream) error {
+			return status.Error(codes.Aborted, "")
+		})
+		assert.Error(t, err)
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.grpcRequestsTotal.WithLabelValues("/random.Service/Stream", codes.Aborted.String())))
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.grpcRequestsInFlight))
+	})
+}
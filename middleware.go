@@ -1,47 +1,73 @@
 package ginney
 
 import (
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
-
-	"github.com/nu7hatch/gouuid"
 )
 
-func CompositeCorrelationIdMiddleware() gin.HandlerFunc {
+func CompositeCorrelationIdMiddleware(opts ...CorrelationIdOption) gin.HandlerFunc {
+	config := newCorrelationIdConfig(opts)
+
 	return func(c *gin.Context) {
 		correlationId := c.Request.Header.Get(CorrelationIdHeaderKey)
 
 		if strings.TrimSpace(correlationId) == "" {
-			id, _ := uuid.NewV4()
-			correlationId = id.String()
+			correlationId = config.generator()
 
 			c.Request.Header.Set(CorrelationIdHeaderKey, correlationId)
 
+		} else if !applyInvalidCorrelationIdPolicy(c, config, correlationId) {
+			return
 		}
 
-		c.Writer.Header().Set(CorrelationIdHeaderKey, correlationId)
+		c.Writer.Header().Set(CorrelationIdHeaderKey, c.Request.Header.Get(CorrelationIdHeaderKey))
 
 		c.Next()
 	}
 }
 
-func MicroServiceCorrelationIdMiddleware() gin.HandlerFunc {
+func MicroServiceCorrelationIdMiddleware(opts ...CorrelationIdOption) gin.HandlerFunc {
+	config := newCorrelationIdConfig(opts)
+
 	return func(c *gin.Context) {
 		correlationId := c.Request.Header.Get(CorrelationIdHeaderKey)
 
 		if strings.TrimSpace(correlationId) == "" {
-			c.AbortWithStatusJSON(http.StatusBadRequest,
-				NewErrorResponse(fmt.Sprintf("%s is missing", CorrelationIdHeaderKey)))
+			JSONError(c, NewError(http.StatusBadRequest, fmt.Sprintf("%s is missing", CorrelationIdHeaderKey)))
+		} else if !applyInvalidCorrelationIdPolicy(c, config, correlationId) {
+			return
 		}
 
 		c.Next()
 	}
 }
 
+func applyInvalidCorrelationIdPolicy(c *gin.Context, config *correlationIdConfig, correlationId string) bool {
+	err := config.validate(correlationId)
+	if err == nil {
+		return true
+	}
+
+	switch config.invalidPolicy {
+	case RejectInvalidCorrelationId:
+		JSONError(c, NewError(http.StatusBadRequest, err.Error()))
+		return false
+	case KeepOriginalCorrelationId:
+		c.Request.Header.Set(OriginalCorrelationIdHeaderKey, correlationId)
+		c.Writer.Header().Set(OriginalCorrelationIdHeaderKey, correlationId)
+	}
+
+	c.Request.Header.Set(CorrelationIdHeaderKey, config.generator())
+	return true
+}
+
 func FromGinContextToContextMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := FromGinContextToContext(c)
@@ -50,7 +76,9 @@ func FromGinContextToContextMiddleware() gin.HandlerFunc {
 	}
 }
 
-func LogWithCorrelationIdMiddleware(out io.Writer, notLogged []string) gin.HandlerFunc {
+func LogWithCorrelationIdMiddleware(out io.Writer, notLogged []string, opts ...LogOption) gin.HandlerFunc {
+	config := newLogConfig(opts)
+
 	var skip map[string]struct{}
 
 	if length := len(notLogged); length > 0 {
@@ -66,9 +94,23 @@ func LogWithCorrelationIdMiddleware(out io.Writer, notLogged []string) gin.Handl
 		path := c.Request.URL.Path
 		raw := c.Request.URL.RawQuery
 
+		_, mustSkip := skip[path]
+
+		// capturing the body before the handler consumes it
+		var body capturedBody
+		var responseWriter *bodyCaptureWriter
+		if !mustSkip {
+			body = captureRequestBody(c.Request, config.maxBodySize)
+
+			if config.logResponseBody {
+				responseWriter = newBodyCaptureWriter(c.Writer, config.maxResponseBodySize)
+				c.Writer = responseWriter
+			}
+		}
+
 		c.Next()
 
-		if _, ok := skip[path]; !ok {
+		if !mustSkip {
 			correlationId := c.Request.Header.Get(CorrelationIdHeaderKey)
 
 			end := time.Now()
@@ -83,18 +125,104 @@ func LogWithCorrelationIdMiddleware(out io.Writer, notLogged []string) gin.Handl
 				path = path + "?" + raw
 			}
 
-			apiName := fmt.Sprintf("%-7s %s", method, path)
-
-			_, _ = fmt.Fprint(out, formatLog(
-				end,
-				correlationId,
-				statusCode,
-				latency,
-				clientIP,
-				apiName,
-				httpRequestBodyToString(c.Request.Body),
-			),
-			)
+			record := LogRecord{
+				Time:          end,
+				CorrelationId: correlationId,
+				Status:        statusCode,
+				Latency:       latency,
+				ClientIp:      clientIP,
+				Method:        method,
+				Path:          path,
+				Body:          body.String(),
+
+				ResponseHeaders: selectHeaders(config.responseHeaders, c.Writer.Header().Get),
+			}
+			if responseWriter != nil {
+				record.ResponseBody = responseWriter.captured().String()
+			}
+
+			_, _ = fmt.Fprint(out, config.formatter.Format(record))
+		}
+	}
+}
+
+// RequestTimeoutMiddleware sets the deadline of the request context from the X-Request-Timeout header in milliseconds,
+// defaultTimeout is used when the header is missing and maxTimeout caps every request, zero disables either of them
+func RequestTimeoutMiddleware(defaultTimeout time.Duration, maxTimeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := defaultTimeout
+		if timeoutMs, err := strconv.ParseInt(c.GetHeader(RequestTimeoutHeaderKey), 10, 64); err == nil && timeoutMs > 0 {
+			timeout = time.Duration(timeoutMs) * time.Millisecond
+		}
+		if maxTimeout > 0 && (timeout <= 0 || timeout > maxTimeout) {
+			timeout = maxTimeout
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+type ErrorHandlerOption func(config *errorHandlerConfig)
+
+type errorHandlerConfig struct {
+	mappers      []func(err error) *Error
+	defaultError *Error
+}
+
+func WithErrorMapper(mapper func(err error) *Error) ErrorHandlerOption {
+	return func(config *errorHandlerConfig) {
+		config.mappers = append(config.mappers, mapper)
+	}
+}
+
+func WithDefaultError(defaultError *Error) ErrorHandlerOption {
+	return func(config *errorHandlerConfig) {
+		config.defaultError = defaultError
+	}
+}
+
+// ErrorHandlerMiddleware renders the last error of c.Errors when the handler wrote nothing. gin's Bind* and
+// MustBindWith write the 400 status themselves, so bind with ShouldBindJSON and pass its *Error to c.Error instead.
+func ErrorHandlerMiddleware(opts ...ErrorHandlerOption) gin.HandlerFunc {
+	config := &errorHandlerConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		ginError := c.Errors.Last()
+		JSONError(c, config.resolve(ginError))
+	}
+}
+
+func (config *errorHandlerConfig) resolve(ginError *gin.Error) error {
+	var ginneyErr *Error
+	if errors.As(ginError.Err, &ginneyErr) {
+		return ginError.Err
+	}
+
+	for _, mapper := range config.mappers {
+		if mapped := mapper(ginError.Err); mapped != nil {
+			return mapped.WithCause(ginError.Err)
 		}
 	}
+
+	if config.defaultError != nil {
+		return config.defaultError.WithCause(ginError.Err)
+	}
+	return ginError.Err
 }
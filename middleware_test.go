@@ -7,8 +7,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLogWithCorrelationIdMiddleware(t *testing.T) {
@@ -339,6 +342,153 @@ func TestLogWithCorrelationIdMiddleware(t *testing.T) {
 		assert.Equal(t, `{"example":"hello","examplePassword":"[HIDDEN_FIELD]","examplePrivateKey":"[HIDDEN_FIELD]","exampleSecretKey":"[HIDDEN_FIELD]"}`+"\n", payload)
 	})
 
+	t.Run("Happy - JSON log formatter", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+
+		// prepare route and register a middleware
+		router := gin.New()
+		router.Use(LogWithCorrelationIdMiddleware(buffer, []string{}, WithLogFormatter(JSONLogFormatter{})))
+		router.POST("/random", func(c *gin.Context) {
+			c.AbortWithStatus(http.StatusCreated)
+		})
+
+		requestBody, _ := json.Marshal(randomJson{
+			Example:         "hello",
+			ExamplePassword: "secret",
+		})
+
+		// send request to the route
+		_ = performRequest(router, "POST", "/random?queryParam=123",
+			bytes.NewBuffer(requestBody),
+			header{
+				Key:   CorrelationIdHeaderKey,
+				Value: "random-uuid",
+			},
+		)
+
+		var line map[string]interface{}
+		err := json.Unmarshal(buffer.Bytes(), &line)
+		assert.NoError(t, err)
+		assert.Equal(t, "random-uuid", line["correlationId"])
+		assert.Equal(t, strconv.Itoa(http.StatusCreated), line["status"])
+		assert.Equal(t, http.MethodPost, line["method"])
+		assert.Equal(t, "/random?queryParam=123", line["path"])
+		assert.Equal(t, map[string]interface{}{"example": "hello", "examplePassword": CensoredFieldText}, line["body"])
+	})
+
+	t.Run("Happy - handler reads the body, body is still logged", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+
+		// prepare route and register a middleware
+		router := gin.New()
+		router.Use(LogWithCorrelationIdMiddleware(buffer, []string{}))
+		router.POST("/random", func(c *gin.Context) {
+			var requestBody randomJson
+			if err := c.ShouldBindJSON(&requestBody); err != nil {
+				c.AbortWithStatus(http.StatusBadRequest)
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusOK, requestBody)
+		})
+
+		requestBody, _ := json.Marshal(randomJson{
+			Example:         "hello",
+			ExamplePassword: "secret",
+		})
+
+		// send request to the route
+		resp := performRequest(router, "POST", "/random",
+			bytes.NewBuffer(requestBody),
+			header{
+				Key:   ContentTypeHeaderKey,
+				Value: "application/json",
+			},
+		)
+
+		_, statusCode, _, payload := extractLogMessage(buffer.String())
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, string(requestBody), resp.Body.String())
+		assert.Equal(t, strconv.Itoa(http.StatusOK), statusCode)
+		assert.Equal(t, `{"example":"hello","examplePassword":"[HIDDEN_FIELD]"}`+"\n", payload)
+	})
+
+	t.Run("Happy - body larger than max body log size", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+
+		// prepare route and register a middleware
+		router := gin.New()
+		router.Use(LogWithCorrelationIdMiddleware(buffer, []string{}, WithMaxBodyLogSize(8)))
+		router.POST("/random", func(c *gin.Context) {
+			var requestBody randomJson
+			if err := c.ShouldBindJSON(&requestBody); err != nil {
+				c.AbortWithStatus(http.StatusBadRequest)
+				return
+			}
+			c.AbortWithStatus(http.StatusOK)
+		})
+
+		requestBody, _ := json.Marshal(randomJson{
+			Example: "hello",
+		})
+
+		// send request to the route
+		resp := performRequest(router, "POST", "/random", bytes.NewBuffer(requestBody))
+
+		_, _, _, payload := extractLogMessage(buffer.String())
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "[truncated body: 19 bytes]\n", payload)
+	})
+
+	t.Run("Happy - response body and headers are logged when enabled", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+
+		// prepare route and register a middleware
+		router := gin.New()
+		router.Use(LogWithCorrelationIdMiddleware(buffer, []string{},
+			WithResponseBodyLogging(0),
+			WithResponseHeaderLogging("X-Ratelimit-Remaining", "X-Not-Set"),
+		))
+		router.GET("/random", func(c *gin.Context) {
+			c.Header("X-Ratelimit-Remaining", "99")
+			c.AbortWithStatusJSON(http.StatusOK, NewSuccessResponse(randomJson{
+				Example:          "hello",
+				ExampleSecretKey: "secret",
+			}))
+		})
+
+		// send request to the route
+		resp := performRequest(router, "GET", "/random", nil)
+
+		_, statusCode, _, payload := extractLogMessage(buffer.String())
+		responseBody, responseHeaders := extractResponseLogMessage(buffer.String())
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, strconv.Itoa(http.StatusOK), statusCode)
+		assert.Equal(t, "{}", payload)
+		assert.Equal(t, `{"data":{"example":"hello","exampleSecretKey":"[HIDDEN_FIELD]"},"message":"OK","status":"success"}`, responseBody)
+		assert.Equal(t, `{"X-Ratelimit-Remaining":"99"}`, responseHeaders)
+	})
+
+	t.Run("Happy - response body larger than max size is truncated", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+
+		// prepare route and register a middleware
+		router := gin.New()
+		router.Use(LogWithCorrelationIdMiddleware(buffer, []string{}, WithResponseBodyLogging(4)))
+		router.GET("/random", func(c *gin.Context) {
+			c.String(http.StatusOK, "hello world")
+		})
+
+		// send request to the route
+		resp := performRequest(router, "GET", "/random", nil)
+
+		responseBody, _ := extractResponseLogMessage(buffer.String())
+
+		assert.Equal(t, "hello world", resp.Body.String())
+		assert.Equal(t, "[text/plain body: 11 bytes]", responseBody)
+	})
 }
 
 func TestFromGinContextToContextMiddleware(t *testing.T) {
@@ -409,6 +559,101 @@ func TestCompositeCorrelationIdMiddleware(t *testing.T) {
 		// send request to the route
 		_ = performRequest(router, "POST", "/random", nil)
 	})
+
+	t.Run("Happy - custom generator", func(t *testing.T) {
+		// prepare route and register a middleware
+		router := gin.New()
+		router.Use(CompositeCorrelationIdMiddleware(WithCorrelationIdGenerator(func() string {
+			return "generated-id"
+		})))
+		router.POST("/random", func(c *gin.Context) {
+			assert.Equal(t, "generated-id", c.Request.Header.Get(CorrelationIdHeaderKey))
+
+			c.AbortWithStatus(http.StatusOK)
+		})
+
+		// send request to the route
+		resp := performRequest(router, "POST", "/random", nil)
+		assert.Equal(t, "generated-id", resp.Header().Get(CorrelationIdHeaderKey))
+	})
+
+	t.Run("Happy - invalid correlation id is replaced", func(t *testing.T) {
+		// prepare route and register a middleware
+		router := gin.New()
+		router.Use(CompositeCorrelationIdMiddleware(
+			WithCorrelationIdGenerator(NewULID),
+			WithCorrelationIdValidators(CorrelationIdMaxLengthValidator(16)),
+		))
+		router.POST("/random", func(c *gin.Context) {
+			assert.Len(t, c.Request.Header.Get(CorrelationIdHeaderKey), 26)
+			assert.Empty(t, c.Request.Header.Get(OriginalCorrelationIdHeaderKey))
+
+			c.AbortWithStatus(http.StatusOK)
+		})
+
+		// send request to the route
+		resp := performRequest(router, "POST", "/random", nil,
+			header{
+				Key:   CorrelationIdHeaderKey,
+				Value: strings.Repeat("garbage", 100),
+			},
+		)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Len(t, resp.Header().Get(CorrelationIdHeaderKey), 26)
+	})
+
+	t.Run("Happy - invalid correlation id is kept as the original one", func(t *testing.T) {
+		// prepare route and register a middleware
+		router := gin.New()
+		router.Use(CompositeCorrelationIdMiddleware(
+			WithCorrelationIdValidators(CorrelationIdPatternValidator(regexp.MustCompile(`^[0-9a-f-]{36}$`))),
+			WithInvalidCorrelationIdPolicy(KeepOriginalCorrelationId),
+		))
+		router.POST("/random", func(c *gin.Context) {
+			assert.NotEqual(t, "random-uuid", c.Request.Header.Get(CorrelationIdHeaderKey))
+			assert.Equal(t, "random-uuid", c.Request.Header.Get(OriginalCorrelationIdHeaderKey))
+
+			c.AbortWithStatus(http.StatusOK)
+		})
+
+		// send request to the route
+		resp := performRequest(router, "POST", "/random", nil,
+			header{
+				Key:   CorrelationIdHeaderKey,
+				Value: "random-uuid",
+			},
+		)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "random-uuid", resp.Header().Get(OriginalCorrelationIdHeaderKey))
+	})
+
+	t.Run("Error - invalid correlation id is rejected", func(t *testing.T) {
+		// prepare route and register a middleware
+		router := gin.New()
+		router.Use(CompositeCorrelationIdMiddleware(
+			WithCorrelationIdValidators(CorrelationIdMaxLengthValidator(5)),
+			WithInvalidCorrelationIdPolicy(RejectInvalidCorrelationId),
+		))
+		router.POST("/random", func(c *gin.Context) {
+			c.AbortWithStatus(http.StatusOK)
+		})
+
+		// send request to the route
+		resp := performRequest(router, "POST", "/random", nil,
+			header{
+				Key:   CorrelationIdHeaderKey,
+				Value: "random-uuid",
+			},
+		)
+
+		var respBody map[string]interface{}
+		err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, StatusFail, respBody["status"])
+		assert.Equal(t, fmt.Sprintf("%s must not be longer than 5 characters", CorrelationIdHeaderKey), respBody["message"])
+	})
 }
 
 func TestMicroServiceCorrelationIdMiddleware(t *testing.T) {
@@ -454,3 +699,171 @@ func TestMicroServiceCorrelationIdMiddleware(t *testing.T) {
 		assert.Equal(t, fmt.Sprintf("%s is missing", CorrelationIdHeaderKey), respBody["message"])
 	})
 }
+
+func TestErrorHandlerMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Happy - render ginney error from c.Errors", func(t *testing.T) {
+		router := gin.New()
+		router.Use(ErrorHandlerMiddleware())
+		router.GET("/accounts/1", func(c *gin.Context) {
+			_ = c.Error(errFakeAccountNotFound.WithHumanMessage("account is missing"))
+		})
+
+		w := performRequest(router, "GET", "/accounts/1", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, `{"status":"fail","code":"ACC-404","message":"account not found","humanMessage":"account is missing"}`, w.Body.String())
+	})
+
+	t.Run("Happy - render last error when several are recorded", func(t *testing.T) {
+		router := gin.New()
+		router.Use(ErrorHandlerMiddleware())
+		router.GET("/accounts/1", func(c *gin.Context) {
+			_ = c.Error(NewError(http.StatusBadRequest, "first"))
+			_ = c.Error(errFakeAccountNotFound)
+		})
+
+		w := performRequest(router, "GET", "/accounts/1", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Happy - bind error from ShouldBindJSON renders bad request", func(t *testing.T) {
+		router := gin.New()
+		router.Use(ErrorHandlerMiddleware())
+		router.POST("/accounts", func(c *gin.Context) {
+			var body struct {
+				Name string `json:"name" binding:"required"`
+			}
+			if err := ShouldBindJSON(c, &body); err != nil {
+				_ = c.Error(err)
+			}
+		})
+
+		w := performRequest(router, "POST", "/accounts", strings.NewReader(`{}`))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, `{"status":"fail","code":"VALIDATION_FAILED","message":"request validation failed","errors":[`+
+			`{"field":"name","rule":"required","message":"name is required"}]}`, w.Body.String())
+	})
+
+	t.Run("Happy - status written by gin's BindJSON is kept", func(t *testing.T) {
+		router := gin.New()
+		router.Use(ErrorHandlerMiddleware())
+		router.POST("/accounts", func(c *gin.Context) {
+			var body struct {
+				Name string `json:"name" binding:"required"`
+			}
+			_ = c.BindJSON(&body)
+		})
+
+		w := performRequest(router, "POST", "/accounts", strings.NewReader(`{}`))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "", w.Body.String())
+	})
+
+	t.Run("Happy - mapper converts domain error", func(t *testing.T) {
+		errDomain := fmt.Errorf("insufficient balance")
+		router := gin.New()
+		router.Use(ErrorHandlerMiddleware(WithErrorMapper(func(err error) *Error {
+			if err == errDomain {
+				return NewError(http.StatusConflict, err.Error()).WithCode("BAL-409")
+			}
+			return nil
+		})))
+		router.POST("/transfers", func(c *gin.Context) {
+			_ = c.Error(errDomain)
+		})
+
+		w := performRequest(router, "POST", "/transfers", nil)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, `{"status":"fail","code":"BAL-409","message":"insufficient balance"}`, w.Body.String())
+	})
+
+	t.Run("Happy - default error hides unknown error", func(t *testing.T) {
+		router := gin.New()
+		router.Use(ErrorHandlerMiddleware(WithDefaultError(NewError(http.StatusInternalServerError, "something went wrong"))))
+		router.GET("/random", func(c *gin.Context) {
+			_ = c.Error(fmt.Errorf("dial tcp: connection refused"))
+		})
+
+		w := performRequest(router, "GET", "/random", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, `{"status":"fail","message":"something went wrong"}`, w.Body.String())
+	})
+
+	t.Run("Happy - response already written is kept", func(t *testing.T) {
+		router := gin.New()
+		router.Use(ErrorHandlerMiddleware())
+		router.GET("/random", func(c *gin.Context) {
+			_ = c.Error(errFakeAccountNotFound)
+			c.String(http.StatusAccepted, "accepted")
+		})
+
+		w := performRequest(router, "GET", "/random", nil)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "accepted", w.Body.String())
+	})
+
+	t.Run("Happy - no error leaves response untouched", func(t *testing.T) {
+		router := gin.New()
+		router.Use(ErrorHandlerMiddleware())
+		router.GET("/random", func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+
+		w := performRequest(router, "GET", "/random", nil)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
+
+func TestRequestTimeoutMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	remainingOf := func(defaultTimeout, maxTimeout time.Duration, headers ...header) time.Duration {
+		var remaining time.Duration
+		router := gin.New()
+		router.Use(RequestTimeoutMiddleware(defaultTimeout, maxTimeout))
+		router.GET("/random", func(c *gin.Context) {
+			if deadline, ok := c.Request.Context().Deadline(); ok {
+				remaining = time.Until(deadline)
+			}
+		})
+		_ = performRequest(router, "GET", "/random", nil, headers...)
+		return remaining
+	}
+
+	t.Run("Happy - deadline from header", func(t *testing.T) {
+		remaining := remainingOf(0, 0, header{Key: RequestTimeoutHeaderKey, Value: "1500"})
+
+		assert.True(t, remaining > time.Second && remaining <= 1500*time.Millisecond)
+	})
+
+	t.Run("Happy - header is capped by max timeout", func(t *testing.T) {
+		remaining := remainingOf(0, 500*time.Millisecond, header{Key: RequestTimeoutHeaderKey, Value: "60000"})
+
+		assert.True(t, remaining > 0 && remaining <= 500*time.Millisecond)
+	})
+
+	t.Run("Happy - default timeout without header", func(t *testing.T) {
+		remaining := remainingOf(200*time.Millisecond, 0)
+
+		assert.True(t, remaining > 0 && remaining <= 200*time.Millisecond)
+	})
+
+	t.Run("Happy - invalid header falls back to default", func(t *testing.T) {
+		remaining := remainingOf(200*time.Millisecond, 0, header{Key: RequestTimeoutHeaderKey, Value: "abc"})
+
+		assert.True(t, remaining > 0 && remaining <= 200*time.Millisecond)
+	})
+
+	t.Run("Happy - no deadline without header and default", func(t *testing.T) {
+		assert.Equal(t, time.Duration(0), remainingOf(0, 0))
+	})
+}
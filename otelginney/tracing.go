@@ -0,0 +1,253 @@
+// Package otelginney adds OpenTelemetry spans to the ginney middleware, interceptors and HTTP client,
+// it is kept apart so that services without tracing don't build OpenTelemetry.
+package otelginney
+
+import (
+	"context"
+	"fmt"
+	"github.com/chaiyawatkit/ginney"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"strings"
+)
+
+const (
+	tracerName = "github.com/chaiyawatkit/ginney"
+
+	CorrelationIdAttributeKey = attribute.Key("correlation.id")
+)
+
+type TracingOption func(config *tracingConfig)
+
+type tracingConfig struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+}
+
+func newTracingConfig(opts []TracingOption) *tracingConfig {
+	config := &tracingConfig{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+func (config *tracingConfig) tracer() trace.Tracer {
+	return config.tracerProvider.Tracer(tracerName)
+}
+
+func WithTracerProvider(tracerProvider trace.TracerProvider) TracingOption {
+	return func(config *tracingConfig) {
+		if tracerProvider != nil {
+			config.tracerProvider = tracerProvider
+		}
+	}
+}
+
+func WithPropagator(propagator propagation.TextMapPropagator) TracingOption {
+	return func(config *tracingConfig) {
+		if propagator != nil {
+			config.propagator = propagator
+		}
+	}
+}
+
+func TracingMiddleware(notTraced []string, opts ...TracingOption) gin.HandlerFunc {
+	config := newTracingConfig(opts)
+	tracer := config.tracer()
+
+	skip := make(map[string]struct{}, len(notTraced))
+	for _, path := range notTraced {
+		skip[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := skip[c.Request.URL.Path]; ok {
+			c.Next()
+			return
+		}
+
+		route := c.FullPath()
+		spanName := route
+		if spanName == "" {
+			spanName = fmt.Sprintf("HTTP %s", c.Request.Method)
+		}
+
+		ctx := config.propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := tracer.Start(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest("", route, c.Request)...),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		// the correlation id middleware may run after this one, so it is read once the request is handled
+		if correlationId := c.Request.Header.Get(ginney.CorrelationIdHeaderKey); correlationId != "" {
+			span.SetAttributes(CorrelationIdAttributeKey.String(correlationId))
+		}
+
+		statusCode := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(statusCode)...)
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(statusCode))
+		if len(c.Errors) > 0 {
+			span.RecordError(c.Errors.Last().Err)
+		}
+	}
+}
+
+func TracingUnaryServerInterceptor(opts ...TracingOption) grpc.UnaryServerInterceptor {
+	config := newTracingConfig(opts)
+	tracer := config.tracer()
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := startGrpcServerSpan(ctx, tracer, config.propagator, info.FullMethod)
+		defer span.End()
+
+		res, handlerErr := handler(ctx, req)
+		endGrpcSpan(span, handlerErr)
+
+		return res, handlerErr
+	}
+}
+
+func TracingStreamServerInterceptor(opts ...TracingOption) grpc.StreamServerInterceptor {
+	config := newTracingConfig(opts)
+	tracer := config.tracer()
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startGrpcServerSpan(ss.Context(), tracer, config.propagator, info.FullMethod)
+		defer span.End()
+
+		handlerErr := handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+		endGrpcSpan(span, handlerErr)
+
+		return handlerErr
+	}
+}
+
+func startGrpcServerSpan(ctx context.Context, tracer trace.Tracer, propagator propagation.TextMapPropagator, fullMethod string) (context.Context, trace.Span) {
+	meta, _ := metadata.FromIncomingContext(ctx)
+	ctx = propagator.Extract(ctx, metadataCarrier(meta.Copy()))
+
+	attributes := []attribute.KeyValue{semconv.RPCSystemKey.String("grpc")}
+	if service, method := splitGrpcFullMethod(fullMethod); service != "" {
+		attributes = append(attributes, semconv.RPCServiceKey.String(service), semconv.RPCMethodKey.String(method))
+	}
+	if correlationId := firstMetadataValue(meta, ginney.CorrelationIdHeaderKey); correlationId != "" {
+		attributes = append(attributes, CorrelationIdAttributeKey.String(correlationId))
+	}
+
+	return tracer.Start(ctx, strings.TrimPrefix(fullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attributes...),
+	)
+}
+
+func endGrpcSpan(span trace.Span, err error) {
+	st, _ := status.FromError(err)
+	span.SetAttributes(attribute.Key("rpc.grpc.status_code").Int64(int64(st.Code())))
+	if st.Code() != codes.OK {
+		span.SetStatus(otelcodes.Error, st.Message())
+	}
+}
+
+func splitGrpcFullMethod(fullMethod string) (service string, method string) {
+	parts := strings.SplitN(strings.TrimPrefix(fullMethod, "/"), "/", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+type clientTracer struct {
+	config *tracingConfig
+}
+
+// NewClientTracer is passed to ginney.WithTracing to create client spans for the ginney HTTP client
+func NewClientTracer(opts ...TracingOption) ginney.ClientTracer {
+	return &clientTracer{config: newTracingConfig(opts)}
+}
+
+func (t *clientTracer) StartClientSpan(ctx context.Context, method string, url string) (context.Context, func(res *http.Response, err error)) {
+	attributes := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(method),
+		semconv.HTTPURLKey.String(url),
+	}
+	if correlationId := ginney.CorrelationIdFromContext(ctx); correlationId != "" {
+		attributes = append(attributes, CorrelationIdAttributeKey.String(correlationId))
+	}
+
+	ctx, span := t.config.tracer().Start(ctx, fmt.Sprintf("HTTP %s", method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...),
+	)
+	return ctx, func(res *http.Response, err error) {
+		endHttpClientSpan(span, res, err)
+		span.End()
+	}
+}
+
+func (t *clientTracer) Inject(ctx context.Context, header http.Header) {
+	t.config.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+func endHttpClientSpan(span trace.Span, res *http.Response, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return
+	}
+
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(res.StatusCode)...)
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(res.StatusCode))
+}
+
+type metadataCarrier metadata.MD
+
+func (carrier metadataCarrier) Get(key string) string {
+	return firstMetadataValue(metadata.MD(carrier), key)
+}
+
+func (carrier metadataCarrier) Set(key string, value string) {
+	metadata.MD(carrier).Set(key, value)
+}
+
+func (carrier metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func firstMetadataValue(meta metadata.MD, key string) string {
+	if values := meta.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
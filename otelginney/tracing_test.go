@@ -0,0 +1,174 @@
+package otelginney
+
+import (
+	"context"
+	"github.com/chaiyawatkit/ginney"
+	"github.com/gin-gonic/gin"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const fakeTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func performRequest(r http.Handler, method, path string, headers map[string]string) {
+	req := httptest.NewRequest(method, path, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	r.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func newInMemoryTracerProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), exporter
+}
+
+func spanAttribute(span tracetest.SpanStub, key string) interface{} {
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value.AsInterface()
+		}
+	}
+	return nil
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Happy - server span continues the incoming trace", func(t *testing.T) {
+		tracerProvider, exporter := newInMemoryTracerProvider()
+
+		router := gin.New()
+		router.Use(TracingMiddleware([]string{"/health"}, WithTracerProvider(tracerProvider)))
+		router.Use(ginney.CompositeCorrelationIdMiddleware())
+		router.GET("/users/:id", func(c *gin.Context) {
+			assert.True(t, trace.SpanContextFromContext(c.Request.Context()).IsValid())
+			c.AbortWithStatus(http.StatusInternalServerError)
+		})
+
+		performRequest(router, "GET", "/users/1", map[string]string{
+			ginney.TraceParentHeaderKey:   fakeTraceParent,
+			ginney.CorrelationIdHeaderKey: "random-uuid",
+		})
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "/users/:id", spans[0].Name)
+		assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+		assert.Equal(t, "random-uuid", spanAttribute(spans[0], string(CorrelationIdAttributeKey)))
+		assert.Equal(t, int64(http.StatusInternalServerError), spanAttribute(spans[0], "http.status_code"))
+		assert.Equal(t, otelcodes.Error, spans[0].Status.Code)
+	})
+
+	t.Run("Happy - no span for skipped path", func(t *testing.T) {
+		tracerProvider, exporter := newInMemoryTracerProvider()
+
+		router := gin.New()
+		router.Use(TracingMiddleware([]string{"/health"}, WithTracerProvider(tracerProvider)))
+		router.GET("/health", func(c *gin.Context) {
+			c.AbortWithStatus(http.StatusOK)
+		})
+
+		performRequest(router, "GET", "/health", nil)
+
+		assert.Len(t, exporter.GetSpans(), 0)
+	})
+}
+
+func TestTracingUnaryServerInterceptor(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		tracerProvider, exporter := newInMemoryTracerProvider()
+
+		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(
+			ginney.CorrelationIdHeaderKey, "random-uuid",
+			ginney.TraceParentHeaderKey, fakeTraceParent,
+		))
+		interceptor := TracingUnaryServerInterceptor(WithTracerProvider(tracerProvider))
+		_, err := interceptor(incomingCtx, nil, &grpc.UnaryServerInfo{FullMethod: "/random.Service/Method"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "not found")
+		})
+		assert.Error(t, err)
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "random.Service/Method", spans[0].Name)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+		assert.Equal(t, "random.Service", spanAttribute(spans[0], "rpc.service"))
+		assert.Equal(t, "Method", spanAttribute(spans[0], "rpc.method"))
+		assert.Equal(t, "random-uuid", spanAttribute(spans[0], string(CorrelationIdAttributeKey)))
+		assert.Equal(t, int64(codes.NotFound), spanAttribute(spans[0], "rpc.grpc.status_code"))
+		assert.Equal(t, otelcodes.Error, spans[0].Status.Code)
+	})
+}
+
+func TestTracingStreamServerInterceptor(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		tracerProvider, exporter := newInMemoryTracerProvider()
+
+		interceptor := TracingStreamServerInterceptor(WithTracerProvider(tracerProvider))
+		err := interceptor(nil, &fakeServerStream{ctx: context.TODO()}, &grpc.StreamServerInfo{FullMethod: "/random.Service/Stream"}, func(srv interface{}, stream grpc.ServerStream) error {
+			assert.True(t, trace.SpanContextFromContext(stream.Context()).IsValid())
+			return nil
+		})
+		assert.NoError(t, err)
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "random.Service/Stream", spans[0].Name)
+		assert.Equal(t, otelcodes.Unset, spans[0].Status.Code)
+	})
+}
+
+func TestClient_Tracing(t *testing.T) {
+	t.Run("Happy - client span is created and propagated", func(t *testing.T) {
+		tracerProvider, exporter := newInMemoryTracerProvider()
+
+		var traceParent string
+		httpmock.Activate()
+		httpmock.RegisterResponder(http.MethodGet, "https://www.fcuk.com/traced", func(req *http.Request) (*http.Response, error) {
+			traceParent = req.Header.Get(ginney.TraceParentHeaderKey)
+			return httpmock.NewStringResponse(http.StatusOK, `{"ping": "pong"}`), nil
+		})
+
+		gc, _ := gin.CreateTestContext(httptest.NewRecorder())
+		gc.Request = httptest.NewRequest(http.MethodGet, "/chaiyawatkit", nil)
+		gc.Request.Header.Set(ginney.CorrelationIdHeaderKey, "random-uuid")
+		ctx, parent := tracerProvider.Tracer("test").Start(ginney.FromGinContextToContext(gc), "parent")
+
+		client := ginney.NewClient(ginney.WithTracing(NewClientTracer(WithTracerProvider(tracerProvider))))
+		_, err := client.Get(ctx, "https://www.fcuk.com/traced")
+		assert.NoError(t, err)
+		parent.End()
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 2)
+		assert.Equal(t, "HTTP GET", spans[0].Name)
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+		assert.Equal(t, "random-uuid", spanAttribute(spans[0], string(CorrelationIdAttributeKey)))
+		assert.Equal(t, int64(http.StatusOK), spanAttribute(spans[0], "http.status_code"))
+		assert.Equal(t, "00-"+spans[0].SpanContext.TraceID().String()+"-"+spans[0].SpanContext.SpanID().String()+"-01", traceParent)
+	})
+}
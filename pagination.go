@@ -0,0 +1,232 @@
+package ginney
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	PageQueryKey   = "page"
+	LimitQueryKey  = "limit"
+	OffsetQueryKey = "offset"
+	CursorQueryKey = "cursor"
+
+	DefaultPageLimit = 20
+	DefaultMaxLimit  = 100
+
+	// MaxPageOffset keeps offsets built from the query within int32 so they cannot overflow into a negative value
+	MaxPageOffset = math.MaxInt32
+)
+
+const ErrorCodeInvalidPagination = "INVALID_PAGINATION"
+
+type PageParams struct {
+	Page   int
+	Limit  int
+	Offset int
+
+	// links keep the style of the request, offset when the client sent offset and page otherwise
+	offsetStyle bool
+}
+
+type CursorParams struct {
+	Cursor string
+	Limit  int
+}
+
+type PaginationOption func(config *paginationConfig)
+
+type paginationConfig struct {
+	defaultLimit int
+	maxLimit     int
+}
+
+func newPaginationConfig(opts []PaginationOption) *paginationConfig {
+	config := &paginationConfig{
+		defaultLimit: DefaultPageLimit,
+		maxLimit:     DefaultMaxLimit,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.defaultLimit > config.maxLimit {
+		config.defaultLimit = config.maxLimit
+	}
+	return config
+}
+
+func WithDefaultLimit(defaultLimit int) PaginationOption {
+	return func(config *paginationConfig) {
+		if defaultLimit > 0 {
+			config.defaultLimit = defaultLimit
+		}
+	}
+}
+
+func WithMaxLimit(maxLimit int) PaginationOption {
+	return func(config *paginationConfig) {
+		if maxLimit > 0 {
+			config.maxLimit = maxLimit
+		}
+	}
+}
+
+// ParsePageParams reads page or offset with limit, the returned error is a 400 *Error ready for JSONError or c.Error
+func ParsePageParams(c *gin.Context, opts ...PaginationOption) (PageParams, error) {
+	config := newPaginationConfig(opts)
+
+	limit, err := config.parseLimit(c)
+	if err != nil {
+		return PageParams{}, err
+	}
+
+	if rawOffset, ok := c.GetQuery(OffsetQueryKey); ok {
+		offset, err := parseNonNegativeInt(OffsetQueryKey, rawOffset, 0)
+		if err != nil {
+			return PageParams{}, err
+		}
+		if offset > MaxPageOffset {
+			return PageParams{}, newPaginationError(fmt.Sprintf("%s must not be greater than %d", OffsetQueryKey, MaxPageOffset))
+		}
+		return PageParams{Page: offset/limit + 1, Limit: limit, Offset: offset, offsetStyle: true}, nil
+	}
+
+	page, err := parseNonNegativeInt(PageQueryKey, c.Query(PageQueryKey), 1)
+	if err != nil {
+		return PageParams{}, err
+	}
+	if page < 1 {
+		return PageParams{}, newPaginationError(fmt.Sprintf("%s must be greater than 0", PageQueryKey))
+	}
+	if page-1 > MaxPageOffset/limit {
+		return PageParams{}, newPaginationError(fmt.Sprintf("%s must not be greater than %d", PageQueryKey, MaxPageOffset/limit+1))
+	}
+	return PageParams{Page: page, Limit: limit, Offset: (page - 1) * limit}, nil
+}
+
+func ParseCursorParams(c *gin.Context, opts ...PaginationOption) (CursorParams, error) {
+	config := newPaginationConfig(opts)
+
+	limit, err := config.parseLimit(c)
+	if err != nil {
+		return CursorParams{}, err
+	}
+	return CursorParams{Cursor: c.Query(CursorQueryKey), Limit: limit}, nil
+}
+
+func (config *paginationConfig) parseLimit(c *gin.Context) (int, error) {
+	limit, err := parseNonNegativeInt(LimitQueryKey, c.Query(LimitQueryKey), config.defaultLimit)
+	if err != nil {
+		return 0, err
+	}
+	if limit < 1 || limit > config.maxLimit {
+		return 0, newPaginationError(fmt.Sprintf("%s must be between 1 and %d", LimitQueryKey, config.maxLimit))
+	}
+	return limit, nil
+}
+
+func parseNonNegativeInt(key, raw string, defaultValue int) (int, error) {
+	if raw == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, newPaginationError(fmt.Sprintf("%s must be a non-negative integer", key))
+	}
+	return value, nil
+}
+
+func newPaginationError(message string) *Error {
+	return NewError(http.StatusBadRequest, message).WithCode(ErrorCodeInvalidPagination)
+}
+
+// NewOffsetPagination builds the pagination block with links relative to the current request,
+// links are left out when the limit is not positive as for params built by hand
+func NewOffsetPagination(c *gin.Context, params PageParams, total int64) Pagination {
+	offset := params.Offset
+	pagination := Pagination{
+		Limit:  params.Limit,
+		Offset: &offset,
+		Total:  &total,
+	}
+	if !params.offsetStyle {
+		page := params.Page
+		pagination.Page = &page
+	}
+	if params.Limit <= 0 {
+		return pagination
+	}
+
+	linkTo := func(offset int) string {
+		if params.offsetStyle {
+			return paginationLink(c.Request.URL, OffsetQueryKey, strconv.Itoa(offset), PageQueryKey)
+		}
+		return paginationLink(c.Request.URL, PageQueryKey, strconv.Itoa(offset/params.Limit+1), OffsetQueryKey)
+	}
+
+	lastOffset := 0
+	if total > 0 {
+		lastOffset = int((total - 1) / int64(params.Limit) * int64(params.Limit))
+	}
+
+	links := &PaginationLinks{
+		Self:  c.Request.URL.RequestURI(),
+		First: linkTo(0),
+		Last:  linkTo(lastOffset),
+	}
+	if params.Offset > 0 {
+		prevOffset := params.Offset - params.Limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		links.Prev = linkTo(prevOffset)
+	}
+	if int64(params.Offset+params.Limit) < total {
+		links.Next = linkTo(params.Offset + params.Limit)
+	}
+	pagination.Links = links
+
+	return pagination
+}
+
+// NewCursorPagination leaves prev and next out when the cursor is empty
+func NewCursorPagination(c *gin.Context, params CursorParams, nextCursor, prevCursor string) Pagination {
+	links := &PaginationLinks{
+		Self: c.Request.URL.RequestURI(),
+	}
+	if nextCursor != "" {
+		links.Next = paginationLink(c.Request.URL, CursorQueryKey, nextCursor)
+	}
+	if prevCursor != "" {
+		links.Prev = paginationLink(c.Request.URL, CursorQueryKey, prevCursor)
+	}
+
+	return Pagination{
+		Limit:      params.Limit,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+		Links:      links,
+	}
+}
+
+func paginationLink(requestUrl *url.URL, key, value string, removedKeys ...string) string {
+	query := requestUrl.Query()
+	query.Set(key, value)
+	for _, removedKey := range removedKeys {
+		query.Del(removedKey)
+	}
+
+	link := url.URL{Path: requestUrl.Path, RawQuery: query.Encode()}
+	return link.RequestURI()
+}
+
+func JSONPaginatedResponse(c *gin.Context, data interface{}, pagination Pagination) {
+	JSONEnvelope(c, http.StatusOK, NewEnvelope(StatusSuccess, MessageOk,
+		WithEnvelopeData(data),
+		WithEnvelopePagination(pagination),
+	))
+}
@@ -0,0 +1,181 @@
+package ginney
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newPaginationTestContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c, w
+}
+
+func TestParsePageParams(t *testing.T) {
+	t.Run("Happy - defaults", func(t *testing.T) {
+		c, _ := newPaginationTestContext("/accounts")
+
+		params, err := ParsePageParams(c)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, params.Page)
+		assert.Equal(t, DefaultPageLimit, params.Limit)
+		assert.Equal(t, 0, params.Offset)
+	})
+
+	t.Run("Happy - page and limit", func(t *testing.T) {
+		c, _ := newPaginationTestContext("/accounts?page=3&limit=10")
+
+		params, err := ParsePageParams(c)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, params.Page)
+		assert.Equal(t, 10, params.Limit)
+		assert.Equal(t, 20, params.Offset)
+	})
+
+	t.Run("Happy - offset wins over page", func(t *testing.T) {
+		c, _ := newPaginationTestContext("/accounts?page=3&offset=5&limit=10")
+
+		params, err := ParsePageParams(c)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, params.Offset)
+		assert.Equal(t, 1, params.Page)
+	})
+
+	t.Run("Error - limit over max", func(t *testing.T) {
+		c, _ := newPaginationTestContext("/accounts?limit=51")
+
+		_, err := ParsePageParams(c, WithMaxLimit(50))
+		ginneyErr := AsError(err)
+		assert.Equal(t, http.StatusBadRequest, ginneyErr.HttpStatus)
+		assert.Equal(t, ErrorCodeInvalidPagination, ginneyErr.Code)
+		assert.Equal(t, "limit must be between 1 and 50", ginneyErr.Message)
+	})
+
+	t.Run("Error - invalid page values", func(t *testing.T) {
+		for _, target := range []string{"/accounts?page=0", "/accounts?page=-1", "/accounts?page=abc", "/accounts?limit=0", "/accounts?offset=-5"} {
+			c, _ := newPaginationTestContext(target)
+
+			_, err := ParsePageParams(c)
+			assert.Equal(t, http.StatusBadRequest, AsError(err).HttpStatus, target)
+		}
+	})
+
+	t.Run("Error - page or offset too large for the offset", func(t *testing.T) {
+		for _, target := range []string{"/accounts?page=922337203685477580&limit=20", "/accounts?page=107374184&limit=20", "/accounts?offset=2147483648"} {
+			c, _ := newPaginationTestContext(target)
+
+			params, err := ParsePageParams(c)
+			assert.Equal(t, PageParams{}, params, target)
+			assert.Equal(t, http.StatusBadRequest, AsError(err).HttpStatus, target)
+			assert.Equal(t, ErrorCodeInvalidPagination, AsError(err).Code, target)
+		}
+
+		c, _ := newPaginationTestContext("/accounts?page=107374183&limit=20")
+		params, err := ParsePageParams(c)
+		assert.NoError(t, err)
+		assert.Equal(t, 2147483640, params.Offset)
+	})
+
+	t.Run("Error - rendered in the fail envelope", func(t *testing.T) {
+		c, w := newPaginationTestContext("/accounts?page=abc")
+
+		_, err := ParsePageParams(c)
+		JSONError(c, err)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, `{"status":"fail","code":"INVALID_PAGINATION","message":"page must be a non-negative integer"}`, w.Body.String())
+	})
+}
+
+func TestParseCursorParams(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		c, _ := newPaginationTestContext("/events?cursor=abc&limit=5")
+
+		params, err := ParseCursorParams(c, WithDefaultLimit(10))
+		assert.NoError(t, err)
+		assert.Equal(t, CursorParams{Cursor: "abc", Limit: 5}, params)
+	})
+
+	t.Run("Happy - default limit", func(t *testing.T) {
+		c, _ := newPaginationTestContext("/events")
+
+		params, err := ParseCursorParams(c, WithDefaultLimit(10))
+		assert.NoError(t, err)
+		assert.Equal(t, CursorParams{Limit: 10}, params)
+	})
+
+	t.Run("Error - limit over max", func(t *testing.T) {
+		c, _ := newPaginationTestContext("/events?limit=1000")
+
+		_, err := ParseCursorParams(c)
+		assert.Equal(t, http.StatusBadRequest, AsError(err).HttpStatus)
+	})
+}
+
+func TestJSONPaginatedResponse(t *testing.T) {
+	t.Run("Happy - page links", func(t *testing.T) {
+		c, w := newPaginationTestContext("/accounts?page=2&limit=10&status=active")
+
+		params, err := ParsePageParams(c)
+		assert.NoError(t, err)
+		JSONPaginatedResponse(c, []string{"a"}, NewOffsetPagination(c, params, 35))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"status":"success","message":"OK","data":["a"],"meta":{"pagination":{"limit":10,"offset":10,"page":2,"total":35,"links":{`+
+			`"self":"/accounts?page=2\u0026limit=10\u0026status=active",`+
+			`"first":"/accounts?limit=10\u0026page=1\u0026status=active",`+
+			`"prev":"/accounts?limit=10\u0026page=1\u0026status=active",`+
+			`"next":"/accounts?limit=10\u0026page=3\u0026status=active",`+
+			`"last":"/accounts?limit=10\u0026page=4\u0026status=active"}}}}`, w.Body.String())
+	})
+
+	t.Run("Happy - offset links on the first page", func(t *testing.T) {
+		c, _ := newPaginationTestContext("/accounts?offset=0&limit=10")
+
+		params, err := ParsePageParams(c)
+		assert.NoError(t, err)
+		pagination := NewOffsetPagination(c, params, 15)
+
+		assert.Nil(t, pagination.Page)
+		assert.Equal(t, "", pagination.Links.Prev)
+		assert.Equal(t, "/accounts?limit=10&offset=10", pagination.Links.Next)
+		assert.Equal(t, "/accounts?limit=10&offset=10", pagination.Links.Last)
+	})
+
+	t.Run("Happy - empty result", func(t *testing.T) {
+		c, _ := newPaginationTestContext("/accounts")
+
+		params, err := ParsePageParams(c)
+		assert.NoError(t, err)
+		pagination := NewOffsetPagination(c, params, 0)
+
+		assert.Equal(t, "", pagination.Links.Next)
+		assert.Equal(t, "/accounts?page=1", pagination.Links.Last)
+	})
+
+	t.Run("Happy - params built by hand without limit have no links", func(t *testing.T) {
+		c, _ := newPaginationTestContext("/accounts")
+
+		pagination := NewOffsetPagination(c, PageParams{}, 35)
+
+		assert.Equal(t, 0, pagination.Limit)
+		assert.Equal(t, int64(35), *pagination.Total)
+		assert.Nil(t, pagination.Links)
+	})
+
+	t.Run("Happy - cursor links", func(t *testing.T) {
+		c, w := newPaginationTestContext("/events?limit=5")
+
+		params, err := ParseCursorParams(c)
+		assert.NoError(t, err)
+		JSONPaginatedResponse(c, []int{1}, NewCursorPagination(c, params, "next-1", ""))
+
+		assert.Equal(t, `{"status":"success","message":"OK","data":[1],"meta":{"pagination":{"limit":5,"nextCursor":"next-1","links":{`+
+			`"self":"/events?limit=5","next":"/events?cursor=next-1\u0026limit=5"}}}}`, w.Body.String())
+	})
+}
@@ -0,0 +1,113 @@
+package ginney
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const ProblemJSONContentType = "application/problem+json"
+
+type ErrorResponseMode int
+
+const (
+	// NegotiateErrorResponse renders problem+json only when the Accept header asks for it
+	NegotiateErrorResponse ErrorResponseMode = iota
+	EnvelopeErrorResponse
+	ProblemErrorResponse
+)
+
+var (
+	// ErrorResponseModeSetting selects how JSONError renders errors for the whole service
+	ErrorResponseModeSetting = NegotiateErrorResponse
+
+	// ProblemTypeBaseUri is prefixed to the business code to build the problem type, about:blank is used when empty
+	ProblemTypeBaseUri = ""
+)
+
+// ProblemDetails is an RFC 7807 document with the ginney extensions
+type ProblemDetails struct {
+	Type          string          `json:"type"`
+	Title         string          `json:"title"`
+	Status        int             `json:"status"`
+	Detail        string          `json:"detail,omitempty"`
+	Instance      string          `json:"instance,omitempty"`
+	CorrelationId string          `json:"correlationId,omitempty"`
+	Code          string          `json:"code,omitempty"`
+	HumanMessage  *string         `json:"humanMessage,omitempty"`
+	Errors        []EnvelopeError `json:"errors,omitempty"`
+	Details       []interface{}   `json:"details,omitempty"`
+}
+
+func NewProblemDetails(c *gin.Context, err error) ProblemDetails {
+	ginneyErr := AsError(err).withDefaults()
+
+	problemType := "about:blank"
+	if ProblemTypeBaseUri != "" && ginneyErr.Code != "" {
+		problemType = strings.TrimSuffix(ProblemTypeBaseUri, "/") + "/" + ginneyErr.Code
+	}
+
+	problem := ProblemDetails{
+		Type:         problemType,
+		Title:        http.StatusText(ginneyErr.HttpStatus),
+		Status:       ginneyErr.HttpStatus,
+		Detail:       ginneyErr.Message,
+		Code:         ginneyErr.Code,
+		HumanMessage: ginneyErr.HumanMessage,
+		Errors:       ginneyErr.Errors,
+		Details:      ginneyErr.Details,
+	}
+	if c.Request != nil {
+		problem.Instance = c.Request.URL.Path
+		problem.CorrelationId = c.Request.Header.Get(CorrelationIdHeaderKey)
+	}
+	return problem
+}
+
+func JSONProblem(c *gin.Context, err error) {
+	problem := NewProblemDetails(c, err)
+
+	jsonBytes, marshalErr := json.Marshal(problem)
+	if marshalErr != nil {
+		// details are the only free-form part, dropping them keeps the response valid
+		problem.Details = nil
+		jsonBytes, _ = json.Marshal(problem)
+	}
+
+	c.Data(problem.Status, ProblemJSONContentType, jsonBytes)
+	c.Abort()
+}
+
+func shouldRenderProblem(c *gin.Context) bool {
+	switch ErrorResponseModeSetting {
+	case ProblemErrorResponse:
+		return true
+	case EnvelopeErrorResponse:
+		return false
+	default:
+		return c.Request != nil && acceptsProblemJSON(c.GetHeader(AcceptHeaderKey))
+	}
+}
+
+func acceptsProblemJSON(accept string) bool {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		params := strings.Split(mediaRange, ";")
+		if !strings.EqualFold(strings.TrimSpace(params[0]), ProblemJSONContentType) {
+			continue
+		}
+
+		for _, param := range params[1:] {
+			keyValue := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(keyValue) != 2 || keyValue[0] != "q" {
+				continue
+			}
+			if quality, err := strconv.ParseFloat(keyValue[1], 64); err == nil && quality <= 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package ginney
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func performErrorRequest(err error, headers ...header) (int, string, string) {
+	router := gin.New()
+	router.GET("/accounts/:id", func(c *gin.Context) {
+		JSONError(c, err)
+	})
+
+	w := performRequest(router, "GET", "/accounts/1", nil, headers...)
+	return w.Code, w.Header().Get(ContentTypeHeaderKey), w.Body.String()
+}
+
+func TestJSONProblem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	humanMessage := "ไม่พบบัญชี"
+
+	t.Run("Happy - negotiated with Accept header", func(t *testing.T) {
+		code, contentType, body := performErrorRequest(errFakeAccountNotFound.WithHumanMessage(humanMessage),
+			header{Key: "Accept", Value: "application/json;q=0.5, application/problem+json"},
+			header{Key: CorrelationIdHeaderKey, Value: "random-uuid"},
+		)
+
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, ProblemJSONContentType, contentType)
+		assert.Equal(t, `{"type":"about:blank","title":"Not Found","status":404,"detail":"account not found","instance":"/accounts/1",`+
+			`"correlationId":"random-uuid","code":"ACC-404","humanMessage":"ไม่พบบัญชี"}`, body)
+	})
+
+	t.Run("Happy - envelope without problem Accept header", func(t *testing.T) {
+		code, contentType, body := performErrorRequest(errFakeAccountNotFound, header{Key: "Accept", Value: "application/json"})
+
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "application/json; charset=utf-8", contentType)
+		assert.Equal(t, `{"status":"fail","code":"ACC-404","message":"account not found"}`, body)
+	})
+
+	t.Run("Happy - problem refused with q=0", func(t *testing.T) {
+		_, contentType, _ := performErrorRequest(errFakeAccountNotFound, header{Key: "Accept", Value: "application/problem+json;q=0"})
+
+		assert.Equal(t, "application/json; charset=utf-8", contentType)
+	})
+
+	t.Run("Happy - problem selected globally with type base uri", func(t *testing.T) {
+		ErrorResponseModeSetting = ProblemErrorResponse
+		ProblemTypeBaseUri = "https://errors.example.com/"
+		defer func() {
+			ErrorResponseModeSetting = NegotiateErrorResponse
+			ProblemTypeBaseUri = ""
+		}()
+
+		code, contentType, body := performErrorRequest(errFakeAccountNotFound)
+
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, ProblemJSONContentType, contentType)
+		assert.Equal(t, `{"type":"https://errors.example.com/ACC-404","title":"Not Found","status":404,"detail":"account not found",`+
+			`"instance":"/accounts/1","code":"ACC-404"}`, body)
+	})
+
+	t.Run("Happy - envelope selected globally ignores Accept header", func(t *testing.T) {
+		ErrorResponseModeSetting = EnvelopeErrorResponse
+		defer func() {
+			ErrorResponseModeSetting = NegotiateErrorResponse
+		}()
+
+		_, contentType, _ := performErrorRequest(errFakeAccountNotFound, header{Key: "Accept", Value: ProblemJSONContentType})
+
+		assert.Equal(t, "application/json; charset=utf-8", contentType)
+	})
+
+	t.Run("Happy - details which cannot be marshalled are dropped", func(t *testing.T) {
+		ErrorResponseModeSetting = ProblemErrorResponse
+		defer func() {
+			ErrorResponseModeSetting = NegotiateErrorResponse
+		}()
+
+		code, _, body := performErrorRequest(errFakeAccountNotFound.WithDetails(make(chan int)).WithCode(""))
+
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, `{"type":"about:blank","title":"Not Found","status":404,"detail":"account not found","instance":"/accounts/1"}`, body)
+	})
+
+	t.Run("Happy - error literal without status", func(t *testing.T) {
+		code, _, body := performErrorRequest(&Error{Message: "boom"}, header{Key: "Accept", Value: ProblemJSONContentType})
+
+		assert.Equal(t, http.StatusInternalServerError, code)
+		assert.Equal(t, `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"boom","instance":"/accounts/1"}`, body)
+	})
+
+	t.Run("Happy - unknown error becomes internal server error", func(t *testing.T) {
+		code, _, body := performErrorRequest(errors.New("dial tcp: connection refused"), header{Key: "Accept", Value: ProblemJSONContentType})
+
+		assert.Equal(t, http.StatusInternalServerError, code)
+		assert.Equal(t, `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"Internal Server Error","instance":"/accounts/1"}`, body)
+	})
+}
+
+func TestJSONProblem_GinneyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ErrorResponseModeSetting = ProblemErrorResponse
+	defer func() {
+		ErrorResponseModeSetting = NegotiateErrorResponse
+	}()
+
+	t.Run("Happy - missing correlation id", func(t *testing.T) {
+		router := gin.New()
+		router.Use(MicroServiceCorrelationIdMiddleware())
+		router.GET("/accounts/:id", func(c *gin.Context) {})
+
+		w := performRequest(router, "GET", "/accounts/1", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, ProblemJSONContentType, w.Header().Get(ContentTypeHeaderKey))
+		assert.Equal(t, `{"type":"about:blank","title":"Bad Request","status":400,"detail":"X-Correlation-ID is missing","instance":"/accounts/1"}`, w.Body.String())
+	})
+
+	t.Run("Happy - rejected correlation id", func(t *testing.T) {
+		router := gin.New()
+		router.Use(CompositeCorrelationIdMiddleware(
+			WithCorrelationIdValidators(CorrelationIdMaxLengthValidator(4)),
+			WithInvalidCorrelationIdPolicy(RejectInvalidCorrelationId),
+		))
+		router.GET("/accounts/:id", func(c *gin.Context) {})
+
+		w := performRequest(router, "GET", "/accounts/1", nil, header{Key: CorrelationIdHeaderKey, Value: "too-long"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, ProblemJSONContentType, w.Header().Get(ContentTypeHeaderKey))
+	})
+
+	t.Run("Happy - recovered panic", func(t *testing.T) {
+		router := gin.New()
+		router.Use(RecoveryMiddleware(ioutil.Discard))
+		router.GET("/accounts/:id", func(c *gin.Context) {
+			panic("boom")
+		})
+
+		w := performRequest(router, "GET", "/accounts/1", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal server error","instance":"/accounts/1"}`, w.Body.String())
+	})
+}
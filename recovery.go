@@ -0,0 +1,182 @@
+package ginney
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const recoveredErrorMessage = "internal server error"
+
+type PanicEvent struct {
+	CorrelationId string
+	Path          string
+	Recovered     interface{}
+	Stack         []byte
+}
+
+// PanicHook is called synchronously after the panic is logged, e.g. for alerting
+type PanicHook func(ctx context.Context, event PanicEvent)
+
+type RecoveryOption func(config *recoveryConfig)
+
+type recoveryConfig struct {
+	formatter LogFormatter
+	hooks     []PanicHook
+}
+
+func newRecoveryConfig(opts []RecoveryOption) *recoveryConfig {
+	config := &recoveryConfig{
+		formatter: TextLogFormatter{},
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+func WithRecoveryLogFormatter(formatter LogFormatter) RecoveryOption {
+	return func(config *recoveryConfig) {
+		if formatter != nil {
+			config.formatter = formatter
+		}
+	}
+}
+
+func WithPanicHook(hook PanicHook) RecoveryOption {
+	return func(config *recoveryConfig) {
+		if hook != nil {
+			config.hooks = append(config.hooks, hook)
+		}
+	}
+}
+
+func (config *recoveryConfig) report(ctx context.Context, out io.Writer, record LogRecord, event PanicEvent) {
+	record.Time = time.Now()
+	record.Body = fmt.Sprintf("panic recovered: %v\n%s", event.Recovered, event.Stack)
+	_, _ = fmt.Fprint(out, config.formatter.Format(record))
+
+	for _, hook := range config.hooks {
+		hook(ctx, event)
+	}
+}
+
+func RecoveryMiddleware(out io.Writer, opts ...RecoveryOption) gin.HandlerFunc {
+	config := newRecoveryConfig(opts)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			correlationId := c.Request.Header.Get(CorrelationIdHeaderKey)
+			brokenPipe := isBrokenPipe(recovered)
+
+			statusCode := http.StatusInternalServerError
+			if brokenPipe || c.Writer.Written() {
+				statusCode = c.Writer.Status()
+			}
+
+			config.report(c.Request.Context(), out, LogRecord{
+				CorrelationId: correlationId,
+				Status:        fmt.Sprintf("%d", statusCode),
+				Latency:       time.Since(start),
+				ClientIp:      c.ClientIP(),
+				Method:        c.Request.Method,
+				Path:          c.Request.URL.Path,
+			}, PanicEvent{
+				CorrelationId: correlationId,
+				Path:          c.Request.URL.Path,
+				Recovered:     recovered,
+				Stack:         debug.Stack(),
+			})
+
+			// the connection is gone or the response has been sent, nothing more can be written
+			if brokenPipe || c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			JSONError(c, NewError(http.StatusInternalServerError, recoveredErrorMessage))
+		}()
+
+		c.Next()
+	}
+}
+
+func RecoveryUnaryServerInterceptor(out io.Writer, opts ...RecoveryOption) grpc.UnaryServerInterceptor {
+	config := newRecoveryConfig(opts)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+		start := time.Now()
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				config.reportGrpc(ctx, out, info.FullMethod, start, recovered)
+				err = status.Error(codes.Internal, recoveredErrorMessage)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+func RecoveryStreamServerInterceptor(out io.Writer, opts ...RecoveryOption) grpc.StreamServerInterceptor {
+	config := newRecoveryConfig(opts)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		start := time.Now()
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				config.reportGrpc(ss.Context(), out, info.FullMethod, start, recovered)
+				err = status.Error(codes.Internal, recoveredErrorMessage)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+func (config *recoveryConfig) reportGrpc(ctx context.Context, out io.Writer, fullMethod string, start time.Time, recovered interface{}) {
+	config.report(ctx, out, LogRecord{
+		CorrelationId: grpcCorrelationIdForLog(ctx),
+		Status:        codes.Internal.String(),
+		Latency:       time.Since(start),
+		ClientIp:      grpcPeerIpForLog(ctx),
+		Path:          fullMethod,
+	}, PanicEvent{
+		CorrelationId: correlationIdFromIncomingContext(ctx),
+		Path:          fullMethod,
+		Recovered:     recovered,
+		Stack:         debug.Stack(),
+	})
+}
+
+func isBrokenPipe(recovered interface{}) bool {
+	netErr, ok := recovered.(*net.OpError)
+	if !ok {
+		return false
+	}
+
+	syscallErr, ok := netErr.Err.(*os.SyscallError)
+	if !ok {
+		return false
+	}
+
+	message := strings.ToLower(syscallErr.Error())
+	return strings.Contains(message, "broken pipe") || strings.Contains(message, "connection reset by peer")
+}
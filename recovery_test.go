@@ -0,0 +1,145 @@
+package ginney
+
+import (
+	"bytes"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Happy - panic is logged with correlation id and rendered as error response", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		var events []PanicEvent
+
+		router := gin.New()
+		router.Use(RecoveryMiddleware(buffer, WithPanicHook(func(ctx context.Context, event PanicEvent) {
+			events = append(events, event)
+		})))
+		router.GET("/random", func(c *gin.Context) {
+			panic("boom")
+		})
+
+		w := performRequest(router, "GET", "/random", nil, header{Key: CorrelationIdHeaderKey, Value: "random-uuid"})
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, `{"status":"fail","message":"internal server error"}`, w.Body.String())
+
+		correlationId, statusCode, apiName, payload := extractLogMessage(buffer.String())
+		assert.Equal(t, "random-uuid", correlationId)
+		assert.Equal(t, "500", statusCode)
+		assert.Equal(t, "GET     /random", apiName)
+		assert.True(t, strings.HasPrefix(payload, "panic recovered: boom\n"))
+		assert.Contains(t, buffer.String(), "runtime/debug.Stack")
+
+		assert.Len(t, events, 1)
+		assert.Equal(t, "random-uuid", events[0].CorrelationId)
+		assert.Equal(t, "/random", events[0].Path)
+		assert.Equal(t, "boom", events[0].Recovered)
+		assert.NotEmpty(t, events[0].Stack)
+	})
+
+	t.Run("Happy - response already written is kept", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+
+		router := gin.New()
+		router.Use(RecoveryMiddleware(buffer))
+		router.GET("/random", func(c *gin.Context) {
+			c.String(http.StatusAccepted, "accepted")
+			panic("boom")
+		})
+
+		w := performRequest(router, "GET", "/random", nil)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "accepted", w.Body.String())
+		assert.Contains(t, buffer.String(), "panic recovered: boom")
+	})
+
+	t.Run("Happy - no panic, nothing is logged", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+
+		router := gin.New()
+		router.Use(RecoveryMiddleware(buffer))
+		router.GET("/random", func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+
+		w := performRequest(router, "GET", "/random", nil)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, buffer.String())
+	})
+}
+
+func TestRecoveryUnaryServerInterceptor(t *testing.T) {
+	req := map[string]interface{}{"id": "1"}
+	info := grpc.UnaryServerInfo{FullMethod: "randomMethod"}
+
+	t.Run("Happy - panic is converted to internal error", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		var events []PanicEvent
+
+		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CorrelationIdHeaderKey, "random-uuid"))
+		interceptor := RecoveryUnaryServerInterceptor(buffer, WithPanicHook(func(ctx context.Context, event PanicEvent) {
+			events = append(events, event)
+		}))
+		res, err := interceptor(incomingCtx, req, &info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			panic("boom")
+		})
+
+		assert.Nil(t, res)
+		assert.Equal(t, codes.Internal, status.Code(err))
+
+		correlationId, statusCode, apiName, payload := extractLogMessage(buffer.String())
+		assert.Equal(t, "random-uuid", correlationId)
+		assert.Equal(t, codes.Internal.String(), statusCode)
+		assert.Equal(t, "randomMethod", apiName)
+		assert.True(t, strings.HasPrefix(payload, "panic recovered: boom\n"))
+
+		assert.Len(t, events, 1)
+		assert.Equal(t, "random-uuid", events[0].CorrelationId)
+		assert.Equal(t, "randomMethod", events[0].Path)
+	})
+
+	t.Run("Happy - no panic, response is passed through", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+
+		interceptor := RecoveryUnaryServerInterceptor(buffer)
+		res, err := interceptor(context.TODO(), req, &info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "ok", nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", res)
+		assert.Empty(t, buffer.String())
+	})
+}
+
+func TestRecoveryStreamServerInterceptor(t *testing.T) {
+	info := grpc.StreamServerInfo{FullMethod: "randomStream"}
+
+	t.Run("Happy - panic is converted to internal error", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+
+		stream := newFakeServerStream(metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CorrelationIdHeaderKey, "random-uuid")))
+		interceptor := RecoveryStreamServerInterceptor(buffer, WithRecoveryLogFormatter(JSONLogFormatter{}))
+		err := interceptor(nil, stream, &info, func(srv interface{}, stream grpc.ServerStream) error {
+			panic("boom")
+		})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Contains(t, buffer.String(), `"correlationId":"random-uuid"`)
+		assert.Contains(t, buffer.String(), `"path":"randomStream"`)
+		assert.Contains(t, buffer.String(), `panic recovered: boom`)
+	})
+}
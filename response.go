@@ -21,11 +21,7 @@ type BaseSuccessResponse struct {
 }
 
 func JSONSuccessResponse(c *gin.Context, data interface{}) {
-	r := new(BaseSuccessResponse)
-	r.Status = StatusSuccess
-	r.Message = MessageOk
-	r.Data = data
-	c.AbortWithStatusJSON(http.StatusOK, *r)
+	JSONEnvelope(c, http.StatusOK, NewEnvelope(StatusSuccess, MessageOk, WithEnvelopeData(data)))
 }
 
 func NewSuccessResponse(data interface{}) BaseSuccessResponse {
@@ -49,77 +45,24 @@ func NewErrorResponse(message string) ErrorResponse {
 	return errorResponse
 }
 
-type successHumanResponse struct {
-	Status       string      `json:"status" example:"fail"`
-	Message      string      `json:"message" example:"Error message will be show here"`
-	HumanMessage *string     `json:"humanMessage" example:"Error message will be show here"`
-	Data         interface{} `json:"data"`
-}
-
-type errorHumanResponse struct {
-	Status       string  `json:"status" example:"fail"`
-	Message      string  `json:"message" example:"Error message will be show here"`
-	HumanMessage *string `json:"humanMessage" example:"Error message will be show here"`
-}
-
 func JSONErrorHumanResponse(c *gin.Context, errCode int, err error, humanMsg *string) {
-
-	errorResponse := errorHumanResponse{
-		Status:  StatusFail,
-		Message: err.Error(),
-	}
-	if humanMsg != nil {
-		errorResponse.HumanMessage = humanMsg
-	}
-
-	c.AbortWithStatusJSON(errCode, errorResponse)
+	JSONEnvelope(c, errCode, NewEnvelope(StatusFail, err.Error(), WithEnvelopeHumanMessage(humanMsg)))
 }
 
 func JSONSuccessHumanResponse(c *gin.Context, data interface{}, humanMsg *string) {
-
-	successResponse := successHumanResponse{
-		Status:  StatusSuccess,
-		Message: MessageOk,
-		Data:    data,
-	}
-	if humanMsg != nil {
-		successResponse.HumanMessage = humanMsg
-	}
-	c.AbortWithStatusJSON(http.StatusOK, successResponse)
-}
-
-type successCodeResponse struct {
-	Status  string      `json:"status" example:"fail"`
-	Code    string      `json:"code" example:"200"`
-	Message string      `json:"message" example:"Error message will be show here"`
-	Data    interface{} `json:"data"`
-}
-
-type errorCodeResponse struct {
-	Status  string `json:"status" example:"fail"`
-	Code    string `json:"code" example:"99"`
-	Message string `json:"message" example:"Error message will be show here"`
+	JSONEnvelope(c, http.StatusOK, NewEnvelope(StatusSuccess, MessageOk,
+		WithEnvelopeHumanMessage(humanMsg),
+		WithEnvelopeData(data),
+	))
 }
 
 func JSONErrorCodeResponse(c *gin.Context, errCode int, err error, code string) {
-
-	errorResponse := errorCodeResponse{
-		Status:  StatusFail,
-		Message: err.Error(),
-		Code:    code,
-	}
-
-	c.AbortWithStatusJSON(errCode, errorResponse)
+	JSONEnvelope(c, errCode, NewEnvelope(StatusFail, err.Error(), WithEnvelopeCode(code)))
 }
 
 func JSONSuccessCodeResponse(c *gin.Context, data interface{}, code string) {
-
-	successResponse := successCodeResponse{
-		Status:  StatusSuccess,
-		Message: MessageOk,
-		Data:    data,
-		Code:    code,
-	}
-
-	c.AbortWithStatusJSON(http.StatusOK, successResponse)
+	JSONEnvelope(c, http.StatusOK, NewEnvelope(StatusSuccess, MessageOk,
+		WithEnvelopeCode(code),
+		WithEnvelopeData(data),
+	))
 }
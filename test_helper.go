@@ -1,12 +1,17 @@
 package ginney
 
 import (
+	"bytes"
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/jarcoal/httpmock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 )
 
 type header struct {
@@ -47,6 +52,30 @@ func performRequest(r http.Handler, method, path string, body io.Reader, headers
 	return w
 }
 
+// syncBuffer is written by the logging go routines while the test reads it
+type syncBuffer struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.String()
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buffer.Bytes()...)
+}
+
 func extractLogMessage(logMsg string) (correlationId string, statusCode string, apiName string, payload string) {
 	cols := strings.Split(logMsg, "|")
 
@@ -56,3 +85,90 @@ func extractLogMessage(logMsg string) (correlationId string, statusCode string,
 
 	return cols[1], cols[2], cols[5], cols[6]
 }
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	incoming []interface{}
+	outgoing []interface{}
+}
+
+func newFakeServerStream(ctx context.Context, incoming ...interface{}) *fakeServerStream {
+	return &fakeServerStream{ctx: ctx, incoming: incoming}
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) SendMsg(m interface{}) error {
+	s.outgoing = append(s.outgoing, m)
+	return nil
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	if len(s.incoming) == 0 {
+		return io.EOF
+	}
+	s.incoming = s.incoming[1:]
+	return nil
+}
+
+type fakeClientStream struct {
+	grpc.ClientStream
+	ctx      context.Context
+	incoming []interface{}
+	outgoing []interface{}
+}
+
+func newFakeClientStream(ctx context.Context, incoming ...interface{}) *fakeClientStream {
+	return &fakeClientStream{ctx: ctx, incoming: incoming}
+}
+
+func (s *fakeClientStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeClientStream) SendMsg(m interface{}) error {
+	s.outgoing = append(s.outgoing, m)
+	return nil
+}
+
+func (s *fakeClientStream) RecvMsg(m interface{}) error {
+	if len(s.incoming) == 0 {
+		return io.EOF
+	}
+	s.incoming = s.incoming[1:]
+	return nil
+}
+
+type fakeServerTransportStream struct {
+	header metadata.MD
+}
+
+func (s *fakeServerTransportStream) Method() string {
+	return ""
+}
+
+func (s *fakeServerTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *fakeServerTransportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *fakeServerTransportStream) SetTrailer(md metadata.MD) error {
+	return nil
+}
+
+func extractResponseLogMessage(logMsg string) (responseBody string, responseHeaders string) {
+	cols := strings.Split(logMsg, "|")
+
+	for index, elem := range cols {
+		cols[index] = strings.Trim(elem, " \n")
+	}
+
+	return cols[7], cols[8]
+}
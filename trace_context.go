@@ -0,0 +1,182 @@
+package ginney
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"strings"
+)
+
+type TraceContext struct {
+	TraceId      string
+	SpanId       string
+	ParentSpanId string
+	Flags        string
+	TraceState   string
+}
+
+func NewTraceContext() TraceContext {
+	return TraceContext{
+		TraceId: hex.EncodeToString(randomBytes(16)),
+		SpanId:  hex.EncodeToString(randomBytes(8)),
+		Flags:   "01",
+	}
+}
+
+func ParseTraceParent(traceParent string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, errors.Errorf("%s is malformed", TraceParentHeaderKey)
+	}
+
+	version, traceId, spanId, flags := parts[0], parts[1], parts[2], parts[3]
+	switch {
+	case !isLowerHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4):
+		return TraceContext{}, errors.Errorf("%s has an unsupported version", TraceParentHeaderKey)
+	case !isLowerHex(traceId, 32) || traceId == strings.Repeat("0", 32):
+		return TraceContext{}, errors.Errorf("%s has an invalid trace id", TraceParentHeaderKey)
+	case !isLowerHex(spanId, 16) || spanId == strings.Repeat("0", 16):
+		return TraceContext{}, errors.Errorf("%s has an invalid parent id", TraceParentHeaderKey)
+	case !isLowerHex(flags, 2):
+		return TraceContext{}, errors.Errorf("%s has invalid flags", TraceParentHeaderKey)
+	}
+
+	return TraceContext{TraceId: traceId, SpanId: spanId, Flags: flags}, nil
+}
+
+func (tc TraceContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-%s", tc.TraceId, tc.SpanId, tc.Flags)
+}
+
+func (tc TraceContext) child() TraceContext {
+	return TraceContext{
+		TraceId:      tc.TraceId,
+		SpanId:       hex.EncodeToString(randomBytes(8)),
+		ParentSpanId: tc.SpanId,
+		Flags:        tc.Flags,
+		TraceState:   tc.TraceState,
+	}
+}
+
+// serverTraceContext continues the incoming trace with a new span id, or starts a new trace
+func serverTraceContext(traceParent string, traceState string) TraceContext {
+	parent, err := ParseTraceParent(traceParent)
+	if err != nil {
+		return NewTraceContext()
+	}
+
+	parent.TraceState = strings.TrimSpace(traceState)
+	return parent.child()
+}
+
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	if tc, ok := ctx.Value(TraceContextKey).(TraceContext); ok {
+		return tc, true
+	}
+
+	ginContext, err := FromContextToGinContext(ctx)
+	if err != nil {
+		return TraceContext{}, false
+	}
+	if value, ok := ginContext.Get(TraceContextKey); ok {
+		tc, ok := value.(TraceContext)
+		return tc, ok
+	}
+	if ginContext.Request != nil {
+		tc, ok := ginContext.Request.Context().Value(TraceContextKey).(TraceContext)
+		return tc, ok
+	}
+	return TraceContext{}, false
+}
+
+func TraceContextMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tc := serverTraceContext(c.GetHeader(TraceParentHeaderKey), c.GetHeader(TraceStateHeaderKey))
+
+		// deriving the correlation id from the trace id when the caller didn't send one
+		if strings.TrimSpace(c.GetHeader(CorrelationIdHeaderKey)) == "" {
+			c.Request.Header.Set(CorrelationIdHeaderKey, tc.TraceId)
+		}
+
+		c.Set(TraceContextKey, tc)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), TraceContextKey, tc))
+
+		c.Next()
+	}
+}
+
+func TraceContextUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withIncomingTraceContext(ctx), req)
+	}
+}
+
+func TraceContextStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: withIncomingTraceContext(ss.Context())})
+	}
+}
+
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func withIncomingTraceContext(ctx context.Context) context.Context {
+	meta, _ := metadata.FromIncomingContext(ctx)
+	meta = meta.Copy()
+
+	tc := serverTraceContext(firstMetadataValue(meta, TraceParentHeaderKey), strings.Join(meta.Get(TraceStateHeaderKey), ","))
+
+	// deriving the correlation id from the trace id when the caller didn't send one
+	if strings.TrimSpace(firstMetadataValue(meta, CorrelationIdHeaderKey)) == "" {
+		meta.Set(CorrelationIdHeaderKey, tc.TraceId)
+		ctx = metadata.NewIncomingContext(ctx, meta)
+	}
+
+	return context.WithValue(ctx, TraceContextKey, tc)
+}
+
+func withOutgoingTraceContext(ctx context.Context) context.Context {
+	if meta, ok := metadata.FromOutgoingContext(ctx); ok && len(meta.Get(TraceParentHeaderKey)) > 0 {
+		return ctx
+	}
+
+	tc, ok := TraceContextFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, TraceParentHeaderKey, tc.TraceParent())
+	if tc.TraceState != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, TraceStateHeaderKey, tc.TraceState)
+	}
+	return ctx
+}
+
+func firstMetadataValue(meta metadata.MD, key string) string {
+	if values := meta.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func isLowerHex(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, char := range value {
+		if !(char >= '0' && char <= '9') && !(char >= 'a' && char <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+package ginney
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"net/http"
+	"testing"
+)
+
+const fakeTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		tc, err := ParseTraceParent(fakeTraceParent)
+		assert.NoError(t, err)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceId)
+		assert.Equal(t, "00f067aa0ba902b7", tc.SpanId)
+		assert.Equal(t, "01", tc.Flags)
+		assert.Equal(t, fakeTraceParent, tc.TraceParent())
+	})
+
+	t.Run("Error - malformed trace parents", func(t *testing.T) {
+		for _, traceParent := range []string{
+			"",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+			"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+			"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		} {
+			_, err := ParseTraceParent(traceParent)
+			assert.Error(t, err, traceParent)
+		}
+	})
+}
+
+func TestTraceContextMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Happy - incoming trace is continued and correlation id is derived", func(t *testing.T) {
+		router := gin.New()
+		router.Use(TraceContextMiddleware())
+		router.Use(CompositeCorrelationIdMiddleware())
+		router.GET("/random", func(c *gin.Context) {
+			tc, ok := TraceContextFromContext(FromGinContextToContext(c))
+			assert.True(t, ok)
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceId)
+			assert.Equal(t, "00f067aa0ba902b7", tc.ParentSpanId)
+			assert.NotEqual(t, "00f067aa0ba902b7", tc.SpanId)
+			assert.Equal(t, "vendor=value", tc.TraceState)
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", c.GetHeader(CorrelationIdHeaderKey))
+
+			c.AbortWithStatus(http.StatusOK)
+		})
+
+		resp := performRequest(router, "GET", "/random", nil,
+			header{Key: TraceParentHeaderKey, Value: fakeTraceParent},
+			header{Key: TraceStateHeaderKey, Value: "vendor=value"},
+		)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Happy - new trace is started and correlation id is kept", func(t *testing.T) {
+		router := gin.New()
+		router.Use(TraceContextMiddleware())
+		router.GET("/random", func(c *gin.Context) {
+			tc, ok := TraceContextFromContext(c.Request.Context())
+			assert.True(t, ok)
+			assert.Len(t, tc.TraceId, 32)
+			assert.Empty(t, tc.ParentSpanId)
+			assert.Equal(t, "random-uuid", c.GetHeader(CorrelationIdHeaderKey))
+
+			c.AbortWithStatus(http.StatusOK)
+		})
+
+		resp := performRequest(router, "GET", "/random", nil,
+			header{Key: TraceParentHeaderKey, Value: "invalid"},
+			header{Key: CorrelationIdHeaderKey, Value: "random-uuid"},
+		)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestTraceContextPropagation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newTracedContext := func() (context.Context, TraceContext) {
+		gc := createGinContextWithCorrelationId(http.MethodGet, "/chaiyawatkit", "random-uuid")
+		tc := serverTraceContext(fakeTraceParent, "vendor=value")
+		gc.Set(TraceContextKey, tc)
+		return FromGinContextToContext(gc), tc
+	}
+
+	t.Run("Happy - http request", func(t *testing.T) {
+		ctx, tc := newTracedContext()
+
+		var traceParent, traceState string
+		httpmock.Activate()
+		httpmock.RegisterResponder(http.MethodGet, "https://www.fcuk.com/traced", func(req *http.Request) (*http.Response, error) {
+			traceParent = req.Header.Get(TraceParentHeaderKey)
+			traceState = req.Header.Get(TraceStateHeaderKey)
+			return httpmock.NewStringResponse(http.StatusOK, `{"ping": "pong"}`), nil
+		})
+
+		_, err := Get(ctx, "https://www.fcuk.com/traced")
+		assert.NoError(t, err)
+		assert.Equal(t, tc.TraceParent(), traceParent)
+		assert.Equal(t, "vendor=value", traceState)
+	})
+
+	t.Run("Happy - grpc outgoing context", func(t *testing.T) {
+		ctx, tc := newTracedContext()
+
+		meta, _ := metadata.FromOutgoingContext(FromContextToGrpcOutgoingContext(ctx))
+		assert.Equal(t, []string{tc.TraceParent()}, meta.Get(TraceParentHeaderKey))
+		assert.Equal(t, []string{"vendor=value"}, meta.Get(TraceStateHeaderKey))
+		assert.Equal(t, []string{"random-uuid"}, meta.Get(CorrelationIdHeaderKey))
+	})
+}
+
+func TestTraceContextUnaryServerInterceptor(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(TraceParentHeaderKey, fakeTraceParent))
+		interceptor := TraceContextUnaryServerInterceptor()
+		_, err := interceptor(incomingCtx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			tc, ok := TraceContextFromContext(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceId)
+			assert.Equal(t, "00f067aa0ba902b7", tc.ParentSpanId)
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", correlationIdFromIncomingContext(ctx))
+			return nil, nil
+		})
+		assert.NoError(t, err)
+	})
+}
+
+func TestTraceContextStreamServerInterceptor(t *testing.T) {
+	t.Run("Happy", func(t *testing.T) {
+		incomingCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CorrelationIdHeaderKey, "random-uuid"))
+		interceptor := TraceContextStreamServerInterceptor()
+		err := interceptor(nil, newFakeServerStream(incomingCtx), &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+			tc, ok := TraceContextFromContext(stream.Context())
+			assert.True(t, ok)
+			assert.Len(t, tc.SpanId, 16)
+			assert.Equal(t, "random-uuid", correlationIdFromIncomingContext(stream.Context()))
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,54 @@
+package ginney
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+type logRoundTripper struct {
+	out    io.Writer
+	next   http.RoundTripper
+	config *logConfig
+}
+
+func NewLogRoundTripper(out io.Writer, next http.RoundTripper, opts ...LogOption) http.RoundTripper {
+	return &logRoundTripper{out: out, next: next, config: newLogConfig(opts)}
+}
+
+func (t *logRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	body := captureOutboundRequestBody(req, t.config.maxBodySize).String()
+
+	res, err := nextRoundTripper(t.next).RoundTrip(req)
+
+	end := time.Now()
+	latency := end.Sub(start)
+
+	statusCode := "-"
+	if err == nil {
+		statusCode = fmt.Sprintf("%d", res.StatusCode)
+	}
+
+	_, _ = fmt.Fprint(t.out, t.config.formatter.Format(LogRecord{
+		Time:          end,
+		CorrelationId: req.Header.Get(CorrelationIdHeaderKey),
+		Status:        statusCode,
+		Latency:       latency,
+		ClientIp:      req.URL.Host,
+		Method:        req.Method,
+		Path:          req.URL.String(),
+		Body:          body,
+	}))
+
+	return res, err
+}
+
+func nextRoundTripper(next http.RoundTripper) http.RoundTripper {
+	// resolving the default transport on every call, so replacing http.DefaultTransport takes effect
+	if next == nil {
+		return http.DefaultTransport
+	}
+	return next
+}
@@ -0,0 +1,55 @@
+package ginney
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestLogRoundTripper(t *testing.T) {
+	t.Run("Happy - request is logged with censored body", func(t *testing.T) {
+		initHttpMock(http.MethodPost, "https://www.fcuk.com/v1/ping", http.StatusCreated, `{"ping": "pong"}`)
+		buffer := new(bytes.Buffer)
+
+		gc := createGinContextWithCorrelationId(http.MethodPost, "/chaiyawatkit", "random-uuid")
+		ctx := FromGinContextToContext(gc)
+
+		requestBody, _ := json.Marshal(randomJson{
+			Example:         "hello",
+			ExamplePassword: "secret",
+		})
+
+		client := NewClient(WithLogging(buffer))
+		resp, err := client.Post(ctx, "https://www.fcuk.com/v1/ping", "application/json", bytes.NewBuffer(requestBody))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		correlationId, statusCode, apiName, payload := extractLogMessage(buffer.String())
+		assert.Equal(t, "random-uuid", correlationId)
+		assert.Equal(t, strconv.Itoa(http.StatusCreated), statusCode)
+		assert.Equal(t, fmt.Sprintf("%-7s %s", http.MethodPost, "https://www.fcuk.com/v1/ping"), apiName)
+		assert.Equal(t, `{"example":"hello","examplePassword":"[HIDDEN_FIELD]"}`+"\n", payload)
+	})
+
+	t.Run("Happy - transport error is logged without status code", func(t *testing.T) {
+		httpmock.Activate()
+		httpmock.RegisterResponder(http.MethodGet, "https://www.fcuk.com/v1/broken", httpmock.NewErrorResponder(errors.New("connection refused")))
+		buffer := new(bytes.Buffer)
+
+		client := NewClient(WithLogging(buffer))
+		_, err := client.Get(context.TODO(), "https://www.fcuk.com/v1/broken")
+		assert.Error(t, err)
+
+		_, statusCode, apiName, payload := extractLogMessage(buffer.String())
+		assert.Equal(t, "-", statusCode)
+		assert.Equal(t, fmt.Sprintf("%-7s %s", http.MethodGet, "https://www.fcuk.com/v1/broken"), apiName)
+		assert.Equal(t, "{}\n", payload)
+	})
+}
@@ -0,0 +1,241 @@
+package ginney
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+const (
+	ErrorCodeValidationFailed = "VALIDATION_FAILED"
+	ErrorCodeInvalidBody      = "INVALID_BODY"
+)
+
+const (
+	ValidationRuleJSON = "json"
+	ValidationRuleType = "type"
+)
+
+// ValidationMessageTemplates maps a validator tag to its message, {field} and {param} are replaced
+var ValidationMessageTemplates = map[string]string{
+	"required":         "{field} is required",
+	"required_with":    "{field} is required when {param} is present",
+	"required_without": "{field} is required when {param} is missing",
+	"min":              "{field} must be at least {param}",
+	"max":              "{field} must be at most {param}",
+	"len":              "{field} must have length {param}",
+	"gt":               "{field} must be greater than {param}",
+	"gte":              "{field} must be greater than or equal to {param}",
+	"lt":               "{field} must be less than {param}",
+	"lte":              "{field} must be less than or equal to {param}",
+	"eq":               "{field} must be equal to {param}",
+	"ne":               "{field} must not be equal to {param}",
+	"oneof":            "{field} must be one of [{param}]",
+	"email":            "{field} must be a valid email",
+	"url":              "{field} must be a valid url",
+	"uuid":             "{field} must be a valid uuid",
+	"numeric":          "{field} must be numeric",
+	"alphanum":         "{field} must contain only letters and numbers",
+	ValidationRuleJSON: "request body is not valid JSON",
+	ValidationRuleType: "{field} must be {param}",
+	"":                 "{field} is invalid",
+}
+
+// ValidationTranslator returns the message of a field error in the given language, or false to fall back to the templates
+type ValidationTranslator func(language string, fieldError EnvelopeError) (string, bool)
+
+type ValidationOption func(config *validationConfig)
+
+type validationConfig struct {
+	templates   map[string]string
+	translators []ValidationTranslator
+	language    string
+}
+
+func newValidationConfig(opts []ValidationOption) *validationConfig {
+	config := &validationConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+func WithValidationTemplates(templates map[string]string) ValidationOption {
+	return func(config *validationConfig) {
+		if config.templates == nil {
+			config.templates = make(map[string]string, len(templates))
+		}
+		for rule, template := range templates {
+			config.templates[rule] = template
+		}
+	}
+}
+
+func WithValidationTranslator(translator ValidationTranslator) ValidationOption {
+	return func(config *validationConfig) {
+		if translator != nil {
+			config.translators = append(config.translators, translator)
+		}
+	}
+}
+
+func WithValidationLanguage(language string) ValidationOption {
+	return func(config *validationConfig) {
+		config.language = language
+	}
+}
+
+// ShouldBindJSON binds the body into obj and translates a failure into a 400 *Error ready for JSONError or c.Error
+func ShouldBindJSON(c *gin.Context, obj interface{}, opts ...ValidationOption) error {
+	err := c.ShouldBindJSON(obj)
+	if err == nil {
+		return nil
+	}
+
+	language := acceptLanguage(c.GetHeader("Accept-Language"))
+	return TranslateBindingError(err, obj, append([]ValidationOption{WithValidationLanguage(language)}, opts...)...)
+}
+
+func TranslateBindingError(err error, obj interface{}, opts ...ValidationOption) *Error {
+	config := newValidationConfig(opts)
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fieldErrors := make([]EnvelopeError, 0, len(validationErrors))
+		for _, fieldError := range validationErrors {
+			fieldErrors = append(fieldErrors, config.translate(EnvelopeError{
+				Field: jsonFieldPath(obj, fieldError.StructNamespace()),
+				Rule:  fieldError.Tag(),
+				Param: fieldError.Param(),
+			}))
+		}
+
+		return NewError(http.StatusBadRequest, "request validation failed").
+			WithCode(ErrorCodeValidationFailed).
+			WithErrors(fieldErrors...).
+			WithCause(err)
+	}
+
+	invalidBody := NewError(http.StatusBadRequest, "invalid request body").WithCode(ErrorCodeInvalidBody).WithCause(err)
+
+	var syntaxError *json.SyntaxError
+	var typeError *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &typeError):
+		return invalidBody.WithErrors(config.translate(EnvelopeError{
+			Field: typeError.Field,
+			Rule:  ValidationRuleType,
+			Param: typeError.Type.String(),
+		}))
+	case errors.As(err, &syntaxError), errors.Is(err, io.ErrUnexpectedEOF):
+		return invalidBody.WithErrors(config.translate(EnvelopeError{Rule: ValidationRuleJSON}))
+	case errors.Is(err, io.EOF):
+		return invalidBody.WithErrors(config.translate(EnvelopeError{Field: "body", Rule: "required"}))
+	}
+	return invalidBody
+}
+
+func (config *validationConfig) translate(fieldError EnvelopeError) EnvelopeError {
+	for _, translator := range config.translators {
+		if message, ok := translator(config.language, fieldError); ok {
+			fieldError.Message = message
+			return fieldError
+		}
+	}
+
+	template, ok := config.templates[fieldError.Rule]
+	if !ok {
+		template, ok = ValidationMessageTemplates[fieldError.Rule]
+	}
+	if !ok {
+		template = ValidationMessageTemplates[""]
+	}
+
+	fieldError.Message = strings.NewReplacer("{field}", fieldError.Field, "{param}", fieldError.Param).Replace(template)
+	return fieldError
+}
+
+// jsonFieldPath converts a struct namespace like CreateAccount.Owners[0].FullName into owners[0].fullName
+func jsonFieldPath(obj interface{}, structNamespace string) string {
+	segments := strings.Split(structNamespace, ".")
+	if len(segments) > 1 {
+		// the first segment is the name of the bound struct
+		segments = segments[1:]
+	}
+
+	var objType reflect.Type
+	if obj != nil {
+		objType = reflect.TypeOf(obj)
+	}
+
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		name, index := segment, ""
+		if bracket := strings.Index(segment, "["); bracket >= 0 {
+			name, index = segment[:bracket], segment[bracket:]
+		}
+
+		objType = indirectType(objType)
+		if objType == nil || objType.Kind() != reflect.Struct {
+			path = append(path, segment)
+			objType = nil
+			continue
+		}
+
+		field, ok := objType.FieldByName(name)
+		if !ok {
+			path = append(path, segment)
+			objType = nil
+			continue
+		}
+
+		// encoding/json flattens embedded structs without a json name, so their fields are at the level of the parent
+		if !isFlattenedField(field) || index != "" {
+			path = append(path, jsonFieldName(field)+index)
+		}
+		objType = field.Type
+		// each index steps into the element type of a slice, array or map
+		for i := strings.Count(index, "["); i > 0 && objType != nil; i-- {
+			objType = indirectType(objType)
+			switch objType.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map:
+				objType = objType.Elem()
+			default:
+				objType = nil
+			}
+		}
+	}
+	return strings.Join(path, ".")
+}
+
+func indirectType(objType reflect.Type) reflect.Type {
+	for objType != nil && objType.Kind() == reflect.Ptr {
+		objType = objType.Elem()
+	}
+	return objType
+}
+
+func isFlattenedField(field reflect.StructField) bool {
+	if !field.Anonymous || strings.Split(field.Tag.Get("json"), ",")[0] != "" {
+		return false
+	}
+	return indirectType(field.Type).Kind() == reflect.Struct
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func acceptLanguage(header string) string {
+	language := strings.Split(header, ",")[0]
+	return strings.TrimSpace(strings.Split(language, ";")[0])
+}
@@ -0,0 +1,141 @@
+package ginney
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type fakeOwner struct {
+	FullName string `json:"fullName" binding:"required"`
+}
+
+type fakeAuditFields struct {
+	RequestId string `json:"requestId" binding:"required"`
+}
+
+type fakeCreateAccountRequest struct {
+	fakeAuditFields
+
+	Name     string      `json:"name,omitempty" binding:"required,max=5"`
+	Currency string      `json:"currency" binding:"oneof=THB USD"`
+	Age      int         `binding:"gte=18"`
+	Owners   []fakeOwner `json:"owners" binding:"dive"`
+}
+
+type fakeTaggedEmbeddedRequest struct {
+	fakeAuditFields `json:"audit"`
+}
+
+func performBindRequest(body string, opts []ValidationOption, headers ...header) (int, string) {
+	router := gin.New()
+	router.POST("/accounts", func(c *gin.Context) {
+		var req fakeCreateAccountRequest
+		if err := ShouldBindJSON(c, &req, opts...); err != nil {
+			JSONError(c, err)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	w := performRequest(router, "POST", "/accounts", strings.NewReader(body), headers...)
+	return w.Code, w.Body.String()
+}
+
+func TestShouldBindJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Happy - valid body", func(t *testing.T) {
+		code, _ := performBindRequest(`{"requestId":"r","name":"a","currency":"THB","Age":20}`, nil)
+
+		assert.Equal(t, http.StatusCreated, code)
+	})
+
+	t.Run("Error - validation errors with json field names", func(t *testing.T) {
+		code, body := performBindRequest(`{"name":"abcdef","currency":"EUR","Age":1,"owners":[{"fullName":""}]}`, nil)
+
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"status":"fail","code":"VALIDATION_FAILED","message":"request validation failed","errors":[`+
+			`{"field":"requestId","rule":"required","message":"requestId is required"},`+
+			`{"field":"name","rule":"max","param":"5","message":"name must be at most 5"},`+
+			`{"field":"currency","rule":"oneof","param":"THB USD","message":"currency must be one of [THB USD]"},`+
+			`{"field":"Age","rule":"gte","param":"18","message":"Age must be greater than or equal to 18"},`+
+			`{"field":"owners[0].fullName","rule":"required","message":"owners[0].fullName is required"}]}`, body)
+	})
+
+	t.Run("Error - type mismatch", func(t *testing.T) {
+		code, body := performBindRequest(`{"name":1}`, nil)
+
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"status":"fail","code":"INVALID_BODY","message":"invalid request body","errors":[`+
+			`{"field":"name","rule":"type","param":"string","message":"name must be string"}]}`, body)
+	})
+
+	t.Run("Error - malformed json", func(t *testing.T) {
+		_, body := performBindRequest(`{"name":`, nil)
+
+		assert.Equal(t, `{"status":"fail","code":"INVALID_BODY","message":"invalid request body","errors":[`+
+			`{"rule":"json","message":"request body is not valid JSON"}]}`, body)
+	})
+
+	t.Run("Error - empty body", func(t *testing.T) {
+		_, body := performBindRequest(``, nil)
+
+		assert.Equal(t, `{"status":"fail","code":"INVALID_BODY","message":"invalid request body","errors":[`+
+			`{"field":"body","rule":"required","message":"body is required"}]}`, body)
+	})
+
+	t.Run("Happy - custom templates and translations", func(t *testing.T) {
+		thai := func(language string, fieldError EnvelopeError) (string, bool) {
+			if language != "th" || fieldError.Rule != "max" {
+				return "", false
+			}
+			return fmt.Sprintf("%s ยาวได้ไม่เกิน %s ตัวอักษร", fieldError.Field, fieldError.Param), true
+		}
+		opts := []ValidationOption{
+			WithValidationTranslator(thai),
+			WithValidationTemplates(map[string]string{"oneof": "{field} is not supported"}),
+		}
+
+		_, body := performBindRequest(`{"name":"abcdef","currency":"EUR","Age":18}`, opts, header{Key: "Accept-Language", Value: "th;q=0.9, en"})
+
+		assert.Contains(t, body, `"message":"name ยาวได้ไม่เกิน 5 ตัวอักษร"`)
+		assert.Contains(t, body, `"message":"currency is not supported"`)
+	})
+}
+
+func TestTranslateBindingError(t *testing.T) {
+	t.Run("Happy - unknown binding error", func(t *testing.T) {
+		err := TranslateBindingError(fmt.Errorf("unknown"), nil)
+
+		assert.Equal(t, http.StatusBadRequest, err.HttpStatus)
+		assert.Equal(t, ErrorCodeInvalidBody, err.Code)
+		assert.Empty(t, err.Errors)
+	})
+
+	t.Run("Happy - field violations in grpc status", func(t *testing.T) {
+		err := NewError(http.StatusBadRequest, "request validation failed").WithErrors(EnvelopeError{Field: "name", Rule: "required", Message: "name is required"})
+
+		details := err.GRPCStatus().Details()
+		assert.Len(t, details, 1)
+		badRequest, ok := details[0].(*errdetails.BadRequest)
+		assert.True(t, ok)
+		assert.Equal(t, "name", badRequest.FieldViolations[0].Field)
+		assert.Equal(t, "name is required", badRequest.FieldViolations[0].Description)
+	})
+
+	t.Run("Happy - struct namespace without bound object", func(t *testing.T) {
+		assert.Equal(t, "Owners[0].FullName", jsonFieldPath(nil, "Request.Owners[0].FullName"))
+		assert.Equal(t, "owners[0].fullName", jsonFieldPath(&fakeCreateAccountRequest{}, "fakeCreateAccountRequest.Owners[0].FullName"))
+		assert.Equal(t, "owners[0].Missing", jsonFieldPath(&fakeCreateAccountRequest{}, "fakeCreateAccountRequest.Owners[0].Missing"))
+	})
+
+	t.Run("Happy - embedded struct fields", func(t *testing.T) {
+		assert.Equal(t, "requestId", jsonFieldPath(&fakeCreateAccountRequest{}, "fakeCreateAccountRequest.fakeAuditFields.RequestId"))
+		assert.Equal(t, "audit.requestId", jsonFieldPath(&fakeTaggedEmbeddedRequest{}, "fakeTaggedEmbeddedRequest.fakeAuditFields.RequestId"))
+	})
+}